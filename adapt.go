@@ -0,0 +1,58 @@
+package mutableware
+
+import "context"
+
+// adaptedHandler runs a handler of one type in a chain of another.
+type adaptedHandler[A any, B any, C any, D any] struct {
+	handler  Handler[A, B]
+	reqConv  func(C) A
+	respConv func(B) D
+	// reqBack and respBack are nil if the handler is terminal.
+	reqBack  func(A) C
+	respBack func(D) B
+}
+
+// AdaptHandler converts h into a Handler for a chain with different Request
+// and Response types. Requests are converted with reqConv before h sees
+// them, and h's response is converted with respConv.
+// h is terminal: the next function it receives returns the zero B, like
+// members of a parallel group. Use AdaptMiddleware if h needs to call
+// through to the rest of the chain.
+func AdaptHandler[A any, B any, C any, D any](h Handler[A, B], reqConv func(C) A, respConv func(B) D) Handler[C, D] {
+	return &adaptedHandler[A, B, C, D]{
+		handler:  h,
+		reqConv:  reqConv,
+		respConv: respConv,
+	}
+}
+
+// AdaptMiddleware is like AdaptHandler, but h can call through to the rest
+// of the chain. Requests h passes to next are converted back with reqBack,
+// and the responses it gets from next are converted back with respBack.
+func AdaptMiddleware[A any, B any, C any, D any](h Handler[A, B], reqConv func(C) A, reqBack func(A) C, respConv func(B) D, respBack func(D) B) Handler[C, D] {
+	return &adaptedHandler[A, B, C, D]{
+		handler:  h,
+		reqConv:  reqConv,
+		respConv: respConv,
+		reqBack:  reqBack,
+		respBack: respBack,
+	}
+}
+
+// AdaptContainer converts hc into a terminal Handler for a chain with
+// different Request and Response types. See AdaptHandler.
+func AdaptContainer[A any, B any, C any, D any](hc *HandlerContainer[A, B], reqConv func(C) A, respConv func(B) D) Handler[C, D] {
+	return AdaptHandler(hc.AsHandler(), reqConv, respConv)
+}
+
+func (a *adaptedHandler[A, B, C, D]) Handle(ctx context.Context, request C, next CurriedHandlerFunc[C, D]) (D, error) {
+	adaptedNext := nilCurriedHandlerFunc[A, B]
+	if a.reqBack != nil {
+		adaptedNext = func(ctx context.Context, request A) (B, error) {
+			response, err := next(ctx, a.reqBack(request))
+			return a.respBack(response), err
+		}
+	}
+	response, err := a.handler.Handle(ctx, a.reqConv(request), adaptedNext)
+	return a.respConv(response), err
+}
@@ -0,0 +1,70 @@
+package mutableware_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptHandler(t *testing.T) {
+	double := mutableware.HandlerFunc[int, int](
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			return request * 2, nil
+		}).Handler()
+
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.Add(mutableware.AdaptHandler(double,
+		func(r string) int {
+			n, _ := strconv.Atoi(r)
+			return n
+		},
+		strconv.Itoa))
+
+	resp, err := hc.Handle(context.Background(), "21")
+	require.NoError(t, err)
+	require.Equal(t, "42", resp)
+}
+
+func TestAdaptMiddleware(t *testing.T) {
+	increment := mutableware.HandlerFunc[int, int](
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			resp, err := next(ctx, request+1)
+			return resp + 1, err
+		}).Handler()
+	atoi := func(r string) int {
+		n, _ := strconv.Atoi(r)
+		return n
+	}
+
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request + "0", nil
+		})
+	hc.Add(mutableware.AdaptMiddleware(increment, atoi, strconv.Itoa, strconv.Itoa, atoi))
+
+	// 1 -> 2 -> "20" -> 21
+	resp, err := hc.Handle(context.Background(), "1")
+	require.NoError(t, err)
+	require.Equal(t, "21", resp)
+}
+
+func TestAdaptContainer(t *testing.T) {
+	lengths := mutableware.NewHandlerContainer[string, int]()
+	lengths.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int]) (int, error) {
+			return len(request), nil
+		})
+
+	hc := mutableware.NewHandlerContainer[[]byte, int64]()
+	hc.Add(mutableware.AdaptContainer(lengths,
+		func(r []byte) string { return string(r) },
+		func(n int) int64 { return int64(n) }))
+
+	resp, err := hc.Handle(context.Background(), []byte("abc"))
+	require.NoError(t, err)
+	require.Equal(t, int64(3), resp)
+}
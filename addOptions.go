@@ -1,9 +1,29 @@
 package mutableware
 
+import (
+	"context"
+	"time"
+)
+
 type builtAddOptions struct {
-	name   string
-	swapID HandlerID
-	last   bool
+	name              string
+	swapID            HandlerID
+	last              bool
+	shadow            bool
+	shadowErrorFn     ShadowErrorFunc
+	canaryID          HandlerID
+	canaryPercent     float64
+	canaryObserve     any
+	fallback          bool
+	retryAttempts     int
+	retryBackoff      BackoffFunc
+	fastPath          bool
+	fastPathThreshold time.Duration
+	mapError          func(error) error
+	deadlineWeight    float64
+	timeout           time.Duration
+	maxConcurrency    int
+	concurrencyQueue  int
 }
 
 // AddOption is an option for the Add(...) function.
@@ -33,6 +53,135 @@ func AddOptionLast() AddOption {
 	}
 }
 
+// ShadowErrorFunc receives errors returned by shadow handlers.
+type ShadowErrorFunc func(ctx context.Context, info HandlerInfo, err error)
+
+// AddOptionShadow makes the handler a shadow handler.
+// Shadow handlers are sent each request on a separate goroutine and can't
+// affect the rest of the chain: the next function they receive
+// returns the zero Response, their response is discarded, and their errors
+// are sent to onError instead of the caller. Panics are reported to onError
+// as a *PanicError. onError may be nil.
+// The shadow gets the same request value the rest of the chain is working
+// on, not a copy, so it must not mutate the request or anything it points to.
+// Use this to try out a new handler against real requests before swapping it in.
+func AddOptionShadow(onError ShadowErrorFunc) AddOption {
+	return func(o *builtAddOptions) {
+		o.shadow = true
+		o.shadowErrorFn = onError
+	}
+}
+
+// AddOptionCanary splits traffic between the target handler and this one.
+// percent (0 to 100) of requests are sent to this handler, and the rest to
+// the target. The pair takes the target's place in the chain and is
+// identified by the returned HandlerID; the target's ID is retired.
+// Promote or roll back the canary by swapping the pair out with AddOptionSwap.
+// If the target handler doesn't exist, normal handler insertion occurs.
+func AddOptionCanary(id HandlerID, percent float64) AddOption {
+	return func(o *builtAddOptions) {
+		o.canaryID = id
+		o.canaryPercent = percent
+	}
+}
+
+// CanaryObserveFunc receives the result of every request that passes
+// through a canary pair. canary is true if the new handler served it.
+type CanaryObserveFunc[Request any, Response any] func(ctx context.Context, request Request, canary bool, result Result[Response])
+
+// AddOptionCanaryObserver attaches an observer to a canary pair so results
+// from the old and new handlers can be compared.
+// The types must match those of the container, or Add panics.
+func AddOptionCanaryObserver[Request any, Response any](observe CanaryObserveFunc[Request, Response]) AddOption {
+	return func(o *builtAddOptions) {
+		o.canaryObserve = observe
+	}
+}
+
+// AddOptionFallback makes the handler a fallback handler instead of part of
+// the main chain. Fallback handlers are only run when the main chain returns
+// an error, and are sent the original request. Newer fallback handlers are
+// invoked first; the next function of the oldest one returns the failed
+// response and error.
+func AddOptionFallback() AddOption {
+	return func(o *builtAddOptions) {
+		o.fallback = true
+	}
+}
+
+// BackoffFunc returns how long to wait before the next attempt.
+// attempt is the number of attempts made so far, starting at 1.
+type BackoffFunc func(attempt int) time.Duration
+
+// AddOptionRetry runs the handler up to attempts times until it succeeds.
+// Each attempt includes the handler's calls to next, so the rest of the
+// chain is retried as well. Only errors that IsRetryable reports as
+// retryable are retried. backoff may be nil to retry immediately.
+func AddOptionRetry(attempts int, backoff BackoffFunc) AddOption {
+	return func(o *builtAddOptions) {
+		o.retryAttempts = attempts
+		o.retryBackoff = backoff
+	}
+}
+
+// AddOptionFastPath makes the handler a fast-path handler instead of part of
+// the main chain. When the context deadline is less than threshold away,
+// the chain skips straight to the fast-path handler instead of starting
+// the next handler, so the request can be answered with a cheap or stale
+// response before time runs out. The next function of a fast-path handler
+// returns the zero Response.
+// If there are several fast-path handlers, the newest one is used.
+func AddOptionFastPath(threshold time.Duration) AddOption {
+	return func(o *builtAddOptions) {
+		o.fastPath = true
+		o.fastPathThreshold = threshold
+	}
+}
+
+// AddOptionMapError transforms errors returned by the handler before they
+// are wrapped. Errors that the handler passes along unchanged from its next
+// function are not transformed, since they came from other handlers.
+func AddOptionMapError(mapError func(error) error) AddOption {
+	return func(o *builtAddOptions) {
+		o.mapError = mapError
+	}
+}
+
+// AddOptionDeadlineWeight gives the handler a share of the context deadline.
+// When a weighted handler is invoked, the time left before the deadline is
+// split between it and the weighted handlers after it in proportion to
+// their weights. The deadline passed to next is shortened so the handler
+// keeps its share for the work it does after next returns.
+func AddOptionDeadlineWeight(weight float64) AddOption {
+	return func(o *builtAddOptions) {
+		o.deadlineWeight = weight
+	}
+}
+
+// AddOptionTimeout limits how long the handler can run, including its call
+// to next. If it runs out of time, the context passed to it is cancelled and
+// the chain returns a *TimeoutError identifying the handler, even if the
+// handler is still running. When combined with AddOptionRetry, each attempt
+// gets the full timeout.
+func AddOptionTimeout(d time.Duration) AddOption {
+	return func(o *builtAddOptions) {
+		o.timeout = d
+	}
+}
+
+// AddOptionMaxConcurrency limits the number of requests that can run the
+// handler at the same time, including its call to next. Up to queue requests
+// wait for their turn until their context is done; any more fail with
+// ErrBusy. Use a queue of 0 to fail fast, or a negative queue to let every
+// request wait. When combined with AddOptionRetry, the handler's slot is
+// given up between attempts.
+func AddOptionMaxConcurrency(n int, queue int) AddOption {
+	return func(o *builtAddOptions) {
+		o.maxConcurrency = n
+		o.concurrencyQueue = queue
+	}
+}
+
 func buildAddOptions(opts []AddOption) *builtAddOptions {
 	built := &builtAddOptions{}
 	for _, opt := range opts {
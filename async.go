@@ -0,0 +1,22 @@
+package mutableware
+
+import "context"
+
+// Result holds the outcome of a single request.
+type Result[Response any] struct {
+	Response Response
+	Err      error
+}
+
+// HandleAsync runs Handle on a new goroutine.
+// The returned channel receives exactly one Result and is then closed.
+// The channel is buffered, so abandoning it will not leak the goroutine.
+func (hc *HandlerContainer[Request, Response]) HandleAsync(ctx context.Context, request Request) <-chan Result[Response] {
+	out := make(chan Result[Response], 1)
+	go func() {
+		defer close(out)
+		response, err := hc.Handle(ctx, request)
+		out <- Result[Response]{Response: response, Err: err}
+	}()
+	return out
+}
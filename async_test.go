@@ -0,0 +1,36 @@
+package mutableware_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleAsync(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, string]) (string, error) {
+			if request < 0 {
+				return "", fmt.Errorf("negative")
+			}
+			return fmt.Sprint(request), nil
+		})
+
+	pending := []<-chan mutableware.Result[string]{}
+	for i := 0; i < 5; i++ {
+		pending = append(pending, hc.HandleAsync(context.Background(), i))
+	}
+	for i, ch := range pending {
+		result := <-ch
+		require.NoError(t, result.Err)
+		require.Equal(t, fmt.Sprint(i), result.Response)
+		_, open := <-ch
+		require.False(t, open)
+	}
+
+	result := <-hc.HandleAsync(context.Background(), -1)
+	require.ErrorIs(t, result.Err, mutableware.ErrHandle)
+}
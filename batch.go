@@ -0,0 +1,84 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+type builtBatchOptions struct {
+	concurrency int
+	queueSize   int
+}
+
+// BatchOption is an option for functions that process many requests at once,
+// like HandleAll(...), Consume(...), and NewWorkerPool(...).
+type BatchOption func(*builtBatchOptions)
+
+// BatchOptionConcurrency limits the number of requests that are handled at
+// the same time. For HandleAll, values less than 1 mean there is no limit.
+// For Consume and NewWorkerPool, values less than 1 mean requests are handled
+// one at a time.
+func BatchOptionConcurrency(n int) BatchOption {
+	return func(o *builtBatchOptions) {
+		o.concurrency = n
+	}
+}
+
+// BatchOptionQueueSize sets how many requests can be waiting to be handled
+// before submitting more blocks. This only applies to NewWorkerPool.
+func BatchOptionQueueSize(n int) BatchOption {
+	return func(o *builtBatchOptions) {
+		o.queueSize = n
+	}
+}
+
+func buildBatchOptions(opts []BatchOption) *builtBatchOptions {
+	built := &builtBatchOptions{}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+// HandleAll runs Handle for each request concurrently.
+// Responses are returned in the same order as their requests. If any
+// requests fail, their errors are joined together and returned alongside
+// the responses of the requests that succeeded. Requests still waiting for
+// their turn when ctx ends aren't handled, and fail with ctx's error.
+func (hc *HandlerContainer[Request, Response]) HandleAll(ctx context.Context, requests []Request, options ...BatchOption) ([]Response, error) {
+	batchOpts := buildBatchOptions(options)
+
+	responses := make([]Response, len(requests))
+	errs := make([]error, len(requests))
+
+	limit := len(requests)
+	if batchOpts.concurrency > 0 && batchOpts.concurrency < limit {
+		limit = batchOpts.concurrency
+	}
+	sem := make(chan struct{}, limit)
+
+	wg := sync.WaitGroup{}
+	for i, request := range requests {
+		i, request := i, request
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			responses[i], errs[i] = hc.Handle(ctx, request)
+		}()
+	}
+	wg.Wait()
+
+	return responses, errors.Join(errs...)
+}
@@ -0,0 +1,68 @@
+package mutableware_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleAll(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			now := running.Add(1)
+			defer running.Add(-1)
+			for {
+				prev := maxRunning.Load()
+				if now <= prev || maxRunning.CompareAndSwap(prev, now) {
+					break
+				}
+			}
+			if request < 0 {
+				return 0, fmt.Errorf("negative")
+			}
+			return request * 2, nil
+		})
+
+	resp, err := hc.HandleAll(context.Background(), []int{1, 2, 3, 4, 5, 6}, mutableware.BatchOptionConcurrency(2))
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 4, 6, 8, 10, 12}, resp)
+	require.LessOrEqual(t, maxRunning.Load(), int32(2))
+
+	resp, err = hc.HandleAll(context.Background(), []int{1, -1, 3})
+	require.ErrorIs(t, err, mutableware.ErrHandle)
+	require.Equal(t, []int{2, 0, 6}, resp)
+
+	resp, err = hc.HandleAll(context.Background(), nil)
+	require.NoError(t, err)
+	require.Empty(t, resp)
+}
+
+func TestHandleAllCanceled(t *testing.T) {
+	var handled atomic.Int32
+	started := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			handled.Add(1)
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err := hc.HandleAll(ctx, []int{1, 2, 3}, mutableware.BatchOptionConcurrency(1))
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, int32(1), handled.Load())
+}
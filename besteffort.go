@@ -0,0 +1,51 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errorCollector gathers handler errors for a single best-effort request.
+type errorCollector struct {
+	mux  sync.Mutex
+	errs []error
+}
+
+func (ec *errorCollector) add(err error) {
+	ec.mux.Lock()
+	defer ec.mux.Unlock()
+	ec.errs = append(ec.errs, err)
+}
+
+func (ec *errorCollector) join() error {
+	ec.mux.Lock()
+	defer ec.mux.Unlock()
+	return errors.Join(ec.errs...)
+}
+
+func contextWithErrorCollector(parent context.Context, collector *errorCollector) context.Context {
+	return context.WithValue(parent, errorCollectorCtxKey, collector)
+}
+
+// handleBestEffort runs a handler, recording its error instead of letting it
+// stop the chain.
+func (hc *HandlerContainer[Request, Response]) handleBestEffort(ctx context.Context, request Request, handler identifiedHandler[Request, Response], next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	tracker := &nextTracker{}
+	out, err := hc.call(ctx, request, handler, trackNext(tracker, next))
+	if err == nil || errors.Is(err, ErrStop) {
+		return out, err
+	}
+
+	// errors from further down the chain have already been recorded.
+	if !tracker.returned(err) {
+		err = hc.wrapError(ctx, handler.info, err)
+		if collector, ok := (ctx.Value(errorCollectorCtxKey)).(*errorCollector); ok {
+			collector.add(err)
+		}
+	}
+	if !tracker.wasCalled() {
+		return next(ctx, request)
+	}
+	return out, err
+}
@@ -0,0 +1,106 @@
+package mutableware_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBestEffort(t *testing.T) {
+	firstErr := fmt.Errorf("first")
+	secondErr := fmt.Errorf("second")
+	ran := []string{}
+
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionBestEffort())
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			ran = append(ran, "base")
+			return "base", nil
+		})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			ran = append(ran, "second")
+			return "", secondErr
+		})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			ran = append(ran, "first")
+			if _, err := next(ctx, request); err != nil {
+				return "", err
+			}
+			return "", firstErr
+		})
+
+	resp, err := hc.Handle(context.Background(), "req")
+	require.Equal(t, []string{"first", "second", "base"}, ran)
+	require.ErrorIs(t, err, firstErr)
+	require.ErrorIs(t, err, secondErr)
+	require.ErrorIs(t, err, mutableware.ErrHandle)
+	require.Equal(t, "", resp)
+}
+
+func TestBestEffortNestedContainer(t *testing.T) {
+	otherErr := fmt.Errorf("other")
+	other := mutableware.NewHandlerContainer[string, string]()
+	other.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "", otherErr
+		})
+
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionBestEffort())
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "base", nil
+		})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			// the error from another container wraps ErrHandle too, but it's
+			// still this handler's error.
+			return other.Handle(ctx, request)
+		})
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, otherErr)
+}
+
+func TestBestEffortNoErrors(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionBestEffort())
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "ok", nil
+		})
+	hc.AddAnonymousHandler(nil)
+
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+}
+
+func TestBestEffortNoErrorWrap(t *testing.T) {
+	firstErr := fmt.Errorf("first")
+	secondErr := fmt.Errorf("second")
+
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionBestEffort(),
+		mutableware.ContainerOptionNoErrorWrap(),
+	)
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			_, _ = next(ctx, request)
+			return "", secondErr
+		})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			// propagate the downstream error; it shouldn't be recorded twice.
+			if _, err := next(ctx, request); err != nil {
+				return "", err
+			}
+			return "", firstErr
+		})
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.Equal(t, "second", err.Error())
+}
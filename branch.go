@@ -0,0 +1,48 @@
+package mutableware
+
+import "context"
+
+// branchHandler sends requests into one of two containers.
+type branchHandler[Request any, Response any] struct {
+	predicate func(Request) bool
+	then      *HandlerContainer[Request, Response]
+	otherwise *HandlerContainer[Request, Response]
+}
+
+// NewBranch creates a Handler that sends requests that predicate returns
+// true for to then, and all other requests to otherwise. Requests that fall
+// through either container rejoin the chain, continuing down the rest of it.
+// Either container can be nil, which sends those requests straight down the
+// rest of the chain.
+func NewBranch[Request any, Response any](predicate func(Request) bool, then *HandlerContainer[Request, Response], otherwise *HandlerContainer[Request, Response]) Handler[Request, Response] {
+	return &branchHandler[Request, Response]{
+		predicate: predicate,
+		then:      then,
+		otherwise: otherwise,
+	}
+}
+
+func (b *branchHandler[Request, Response]) branch(request Request) *HandlerContainer[Request, Response] {
+	if b.predicate(request) {
+		return b.then
+	}
+	return b.otherwise
+}
+
+func (b *branchHandler[Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	hc := b.branch(request)
+	if hc == nil {
+		return next(ctx, request)
+	}
+	return hc.HandleNext(ctx, request, next)
+}
+
+// Explain reports the branch as active if any handler in the container the
+// request would be sent to is.
+func (b *branchHandler[Request, Response]) Explain(ctx context.Context, request Request) bool {
+	hc := b.branch(request)
+	if hc == nil {
+		return false
+	}
+	return hc.AsHandler().(Explainer[Request]).Explain(ctx, request)
+}
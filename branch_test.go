@@ -0,0 +1,47 @@
+package mutableware_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranch(t *testing.T) {
+	admin := mutableware.NewHandlerContainer[string, string]()
+	admin.AddAnonymousHandler(appendHandler(">admin"))
+	user := mutableware.NewHandlerContainer[string, string]()
+	user.AddAnonymousHandler(appendHandler(">user"))
+
+	isAdmin := func(r string) bool { return strings.HasPrefix(r, "admin") }
+
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request + ">done", nil
+		})
+	hc.Add(mutableware.NewBranch(isAdmin, admin, user))
+
+	resp, err := hc.Handle(context.Background(), "admin")
+	require.NoError(t, err)
+	require.Equal(t, "admin>admin>done", resp)
+
+	resp, err = hc.Handle(context.Background(), "bob")
+	require.NoError(t, err)
+	require.Equal(t, "bob>user>done", resp)
+
+	onlyAdmin := mutableware.NewHandlerContainer[string, string]()
+	onlyAdmin.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request + ">done", nil
+		})
+	onlyAdmin.Add(mutableware.NewBranch(isAdmin, admin, nil))
+
+	resp, err = onlyAdmin.Handle(context.Background(), "bob")
+	require.NoError(t, err)
+	require.Equal(t, "bob>done", resp)
+	require.False(t, onlyAdmin.Explain(context.Background(), "bob")[0].Active)
+	require.True(t, onlyAdmin.Explain(context.Background(), "admin")[0].Active)
+}
@@ -0,0 +1,25 @@
+package mutableware
+
+import (
+	"context"
+	"time"
+)
+
+// budgetNext shortens the deadline passed to next so that the handler keeps
+// its share of the time that's left. ctx is the context the handler was
+// invoked with.
+func budgetNext[Request any, Response any](ctx context.Context, clock Clock, handler identifiedHandler[Request, Response], next CurriedHandlerFunc[Request, Response]) CurriedHandlerFunc[Request, Response] {
+	deadline, ok := ctx.Deadline()
+	if !ok || handler.downstreamWeight <= 0 {
+		return next
+	}
+	remaining := until(clock, deadline)
+	reserved := time.Duration(float64(remaining) * handler.weight / (handler.weight + handler.downstreamWeight))
+	nextDeadline := deadline.Add(-reserved)
+
+	return func(cx context.Context, request Request) (Response, error) {
+		budgetCtx, cancel := withDeadlineCause(cx, clock, nextDeadline, nil)
+		defer cancel()
+		return next(budgetCtx, request)
+	}
+}
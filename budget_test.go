@@ -0,0 +1,39 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadlineWeight(t *testing.T) {
+	remaining := map[string]time.Duration{}
+	record := func(name string) mutableware.HandlerFunc[string, string] {
+		return func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			remaining[name] = time.Until(deadline)
+			return next(ctx, request)
+		}
+	}
+
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(record("inner"), mutableware.AddOptionDeadlineWeight(1))
+	hc.AddAnonymousHandler(record("unweighted"))
+	hc.AddAnonymousHandler(record("middle"), mutableware.AddOptionDeadlineWeight(1))
+	hc.AddAnonymousHandler(record("outer"), mutableware.AddOptionDeadlineWeight(2))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 40*time.Second)
+	defer cancel()
+	_, err := hc.Handle(ctx, "req")
+	require.NoError(t, err)
+
+	// outer keeps half, middle keeps half of what's left, inner gets the rest.
+	require.InDelta(t, 40*time.Second, remaining["outer"], float64(time.Second))
+	require.InDelta(t, 20*time.Second, remaining["middle"], float64(time.Second))
+	require.InDelta(t, 10*time.Second, remaining["unweighted"], float64(time.Second))
+	require.InDelta(t, 10*time.Second, remaining["inner"], float64(time.Second))
+}
@@ -0,0 +1,95 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrUnregistered is returned when a Bus has no container for a message.
+var ErrUnregistered = errors.New("unregistered")
+
+// busRoute is a container registered on a Bus.
+type busRoute struct {
+	// container is a *HandlerContainer of some Request and Response type.
+	container any
+	// send handles an untyped message.
+	send func(ctx context.Context, message any) (any, error)
+}
+
+// Bus holds containers for many Request types, and sends each message to
+// the container for its type. Register containers with Register.
+// A Bus is safe for concurrent use.
+type Bus struct {
+	routes map[reflect.Type]busRoute
+	mux    *sync.RWMutex
+}
+
+// NewBus creates an empty Bus.
+func NewBus() *Bus {
+	return &Bus{
+		routes: map[reflect.Type]busRoute{},
+		mux:    &sync.RWMutex{},
+	}
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Register makes bus send messages of type Request to hc, replacing any
+// container already registered for Request.
+func Register[Request any, Response any](bus *Bus, hc *HandlerContainer[Request, Response]) {
+	bus.mux.Lock()
+	defer bus.mux.Unlock()
+	bus.routes[typeOf[Request]()] = busRoute{
+		container: hc,
+		send: func(ctx context.Context, message any) (any, error) {
+			return hc.Handle(ctx, message.(Request))
+		},
+	}
+}
+
+// Unregister removes the container for messages of type Request.
+// It returns false if there was no such container.
+func Unregister[Request any](bus *Bus) bool {
+	bus.mux.Lock()
+	defer bus.mux.Unlock()
+	key := typeOf[Request]()
+	_, ok := bus.routes[key]
+	delete(bus.routes, key)
+	return ok
+}
+
+// Dispatch sends a request to the container registered on bus for its type.
+// ErrUnregistered is returned if there is no container for Request, or if
+// its Response type isn't Response.
+func Dispatch[Request any, Response any](ctx context.Context, bus *Bus, request Request) (Response, error) {
+	bus.mux.RLock()
+	route, ok := bus.routes[typeOf[Request]()]
+	bus.mux.RUnlock()
+
+	hc, typed := route.container.(*HandlerContainer[Request, Response])
+	if !ok || !typed {
+		var zero Response
+		return zero, fmt.Errorf("%w: %s to %s", ErrUnregistered, typeOf[Request](), typeOf[Response]())
+	}
+	return hc.Handle(ctx, request)
+}
+
+// Send sends a message to the container registered for its dynamic type,
+// for callers that don't know the message's type at compile time.
+// ErrUnregistered is returned if there is no such container.
+func (b *Bus) Send(ctx context.Context, message any) (any, error) {
+	messageType := reflect.TypeOf(message)
+	b.mux.RLock()
+	route, ok := b.routes[messageType]
+	b.mux.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnregistered, messageType)
+	}
+	return route.send(ctx, message)
+}
@@ -0,0 +1,50 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+type createUser struct {
+	Name string
+}
+
+type deleteUser struct {
+	ID int
+}
+
+func TestBus(t *testing.T) {
+	creates := mutableware.NewHandlerContainer[createUser, int]()
+	creates.AddAnonymousHandler(
+		func(ctx context.Context, request createUser, next mutableware.CurriedHandlerFunc[createUser, int]) (int, error) {
+			return len(request.Name), nil
+		})
+	deletes := mutableware.NewHandlerContainer[deleteUser, bool]()
+	deletes.AddAnonymousHandler(
+		func(ctx context.Context, request deleteUser, next mutableware.CurriedHandlerFunc[deleteUser, bool]) (bool, error) {
+			return request.ID > 0, nil
+		})
+
+	bus := mutableware.NewBus()
+	mutableware.Register(bus, creates)
+	mutableware.Register(bus, deletes)
+
+	id, err := mutableware.Dispatch[createUser, int](context.Background(), bus, createUser{Name: "abc"})
+	require.NoError(t, err)
+	require.Equal(t, 3, id)
+
+	deleted, err := bus.Send(context.Background(), deleteUser{ID: 1})
+	require.NoError(t, err)
+	require.Equal(t, true, deleted)
+
+	// the Response type must match.
+	_, err = mutableware.Dispatch[createUser, string](context.Background(), bus, createUser{})
+	require.ErrorIs(t, err, mutableware.ErrUnregistered)
+
+	require.True(t, mutableware.Unregister[deleteUser](bus))
+	_, err = bus.Send(context.Background(), deleteUser{ID: 1})
+	require.ErrorIs(t, err, mutableware.ErrUnregistered)
+}
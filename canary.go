@@ -0,0 +1,28 @@
+package mutableware
+
+import (
+	"context"
+	"math/rand"
+)
+
+// canaryHandler sends a percentage of requests to a candidate handler
+// and the rest to the old one.
+type canaryHandler[Request any, Response any] struct {
+	old       Handler[Request, Response]
+	candidate Handler[Request, Response]
+	percent   float64
+	observe   CanaryObserveFunc[Request, Response]
+}
+
+func (c *canaryHandler[Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	canary := rand.Float64()*100 < c.percent
+	handler := c.old
+	if canary {
+		handler = c.candidate
+	}
+	response, err := handler.Handle(ctx, request, next)
+	if c.observe != nil {
+		c.observe(ctx, request, canary, Result[Response]{Response: response, Err: err})
+	}
+	return response, err
+}
@@ -0,0 +1,93 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanary(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	oldID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "old", nil
+		})
+
+	served := map[bool]int{}
+	canaryID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "new", nil
+		},
+		mutableware.AddOptionCanary(oldID, 50),
+		mutableware.AddOptionCanaryObserver(func(ctx context.Context, request string, canary bool, result mutableware.Result[string]) {
+			if canary {
+				require.Equal(t, "new", result.Response)
+			} else {
+				require.Equal(t, "old", result.Response)
+			}
+			served[canary]++
+		}),
+	)
+
+	for i := 0; i < 200; i++ {
+		_, err := hc.Handle(context.Background(), "req")
+		require.NoError(t, err)
+	}
+	require.Positive(t, served[true])
+	require.Positive(t, served[false])
+	require.Equal(t, 200, served[true]+served[false])
+
+	// promote the canary
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "new", nil
+		},
+		mutableware.AddOptionSwap(canaryID),
+	)
+	for i := 0; i < 20; i++ {
+		resp, err := hc.Handle(context.Background(), "req")
+		require.NoError(t, err)
+		require.Equal(t, "new", resp)
+	}
+}
+
+func TestCanaryAll(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	oldID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "old", nil
+		})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "new", nil
+		},
+		mutableware.AddOptionCanary(oldID, 100),
+	)
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "new", resp)
+}
+
+func TestCanaryObserverTypeMismatch(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	oldID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "old", nil
+		})
+	require.Panics(t, func() {
+		hc.AddAnonymousHandler(
+			func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+				return "new", nil
+			},
+			mutableware.AddOptionCanary(oldID, 100),
+			mutableware.AddOptionCanaryObserver(func(ctx context.Context, request int, canary bool, result mutableware.Result[string]) {}),
+		)
+	})
+
+	// the container is left as it was.
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "old", resp)
+}
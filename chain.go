@@ -0,0 +1,62 @@
+package mutableware
+
+import "context"
+
+// chain is an immutable snapshot of the handlers in a container, in the
+// order they're invoked. Requests move through it with a cursor: the next
+// function passed to each handler invokes the handler after it.
+type chain[Request any, Response any] struct {
+	hc       *HandlerContainer[Request, Response]
+	handlers []identifiedHandler[Request, Response]
+	// nexts[i] invokes handlers[i]. The last entry is the terminal.
+	nexts       []CurriedHandlerFunc[Request, Response]
+	fastPath    fastPathHandler[Request, Response]
+	hasFastPath bool
+}
+
+// newChain builds a chain out of stacks of handlers that ends in terminal.
+// Each stack is oldest first, and handlers in later stacks run first.
+// The lock must be held.
+func (hc *HandlerContainer[Request, Response]) newChain(terminal CurriedHandlerFunc[Request, Response], stacks ...[]identifiedHandler[Request, Response]) *chain[Request, Response] {
+	c := &chain[Request, Response]{hc: hc}
+	for i := len(stacks) - 1; i >= 0; i-- {
+		for j := len(stacks[i]) - 1; j >= 0; j-- {
+			c.handlers = append(c.handlers, stacks[i][j])
+		}
+	}
+	c.fastPath, c.hasFastPath = hc.currentFastPath()
+
+	downstreamWeight := float64(0)
+	for i := len(c.handlers) - 1; i >= 0; i-- {
+		c.handlers[i].downstreamWeight = downstreamWeight
+		downstreamWeight += c.handlers[i].weight
+	}
+
+	c.nexts = make([]CurriedHandlerFunc[Request, Response], len(c.handlers)+1)
+	for i := range c.handlers {
+		i := i
+		c.nexts[i] = func(ctx context.Context, request Request) (Response, error) {
+			return c.step(ctx, request, i)
+		}
+	}
+	c.nexts[len(c.handlers)] = terminal
+	return c
+}
+
+// handle sends a request into the top of the chain.
+func (c *chain[Request, Response]) handle(ctx context.Context, request Request) (Response, error) {
+	if len(c.handlers) == 1 {
+		// skip the hop through nexts for the most common chain.
+		// An empty chain goes straight to the terminal.
+		return c.step(ctx, request, 0)
+	}
+	return c.nexts[0](ctx, request)
+}
+
+// step invokes the handler at the cursor.
+func (c *chain[Request, Response]) step(ctx context.Context, request Request, cursor int) (Response, error) {
+	if c.hasFastPath && c.fastPath.due(ctx, c.hc.opts.clock) {
+		return c.hc.invoke(ctx, request, c.fastPath.identifiedHandler, c.hc.fallThrough)
+	}
+	return c.hc.invoke(ctx, request, c.handlers[cursor], c.nexts[cursor+1])
+}
@@ -0,0 +1,117 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLongChain(t *testing.T) {
+	depth := 0
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			depth = len(mutableware.GetHandlerInfoFromContext(ctx))
+			return request, nil
+		})
+	ids := make([]mutableware.HandlerID, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		ids = append(ids, hc.AddAnonymousHandler(
+			func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+				return next(ctx, request+1)
+			}))
+	}
+
+	resp, err := hc.Handle(context.Background(), 0)
+	require.NoError(t, err)
+	require.Equal(t, 1000, resp)
+	require.Equal(t, 1001, depth)
+
+	// removing from the middle keeps the rest of the chain intact.
+	for _, id := range ids[200:400] {
+		hc.Remove(id)
+	}
+	resp, err = hc.Handle(context.Background(), 0)
+	require.NoError(t, err)
+	require.Equal(t, 800, resp)
+	require.Equal(t, 801, depth)
+}
+
+// TestRebuildAfterRequest confirms that changes made after a request has
+// been handled are seen by the next one.
+func TestRebuildAfterRequest(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, int]()
+	fail := hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			return 0, errors.New("failed")
+		})
+	_, err := hc.Handle(context.Background(), 0)
+	require.Error(t, err)
+
+	mutableware.ErrorHandlers(hc).AddAnonymousHandler(
+		func(ctx context.Context, request mutableware.Failure[int, int], next mutableware.CurriedHandlerFunc[mutableware.Failure[int, int], int]) (int, error) {
+			return -1, nil
+		})
+	resp, err := hc.Handle(context.Background(), 0)
+	require.NoError(t, err)
+	require.Equal(t, -1, resp)
+
+	hc.Remove(fail)
+	resp, err = hc.Handle(context.Background(), 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, resp)
+}
+
+// TestTinyChainAllocs confirms that short chains don't allocate when
+// handler info is turned off.
+func TestTinyChainAllocs(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, int](mutableware.ContainerOptionWithoutHandlerInfo())
+	ctx := context.Background()
+	handle := func() {
+		_, _ = hc.Handle(ctx, 1)
+	}
+	handle()
+	require.Zero(t, testing.AllocsPerRun(100, handle))
+
+	hc.AddAnonymousHandler(func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+		return next(ctx, request)
+	})
+	handle()
+	require.Zero(t, testing.AllocsPerRun(100, handle))
+}
+
+func BenchmarkHandleEmpty(b *testing.B) {
+	hc := mutableware.NewHandlerContainer[int, int](mutableware.ContainerOptionWithoutHandlerInfo())
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = hc.Handle(ctx, i)
+	}
+}
+
+func BenchmarkHandleOne(b *testing.B) {
+	hc := mutableware.NewHandlerContainer[int, int](mutableware.ContainerOptionWithoutHandlerInfo())
+	hc.AddAnonymousHandler(func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+		return request, nil
+	})
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = hc.Handle(ctx, i)
+	}
+}
+
+func BenchmarkHandleOneWithHandlerInfo(b *testing.B) {
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.AddAnonymousHandler(func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+		return request, nil
+	})
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = hc.Handle(ctx, i)
+	}
+}
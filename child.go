@@ -0,0 +1,30 @@
+package mutableware
+
+import "slices"
+
+// NewChildContainer creates a container whose chain falls through to the
+// parent's chain. The child's handlers run first, and requests that fall
+// through them are handled by the parent, including its fallback and error
+// handlers. Changes to the parent are seen by the child, but changes to the
+// child never affect the parent.
+//
+// The child starts with the parent's container options, and options
+// are applied on top of them. The parent's registry isn't applied again,
+// since its handlers already run in the parent, and the child doesn't take
+// the parent's name, expvar name, or terminal handler.
+func NewChildContainer[Request any, Response any](parent *HandlerContainer[Request, Response], options ...ContainerOption) *HandlerContainer[Request, Response] {
+	opts := *parent.opts
+	// options append to these, so the child needs its own copies.
+	opts.observers = slices.Clone(opts.observers)
+	opts.skipRegistered = slices.Clone(opts.skipRegistered)
+	opts.registry = nil
+	opts.expvarName = ""
+	opts.name = ""
+	opts.bottom = nil
+	for _, opt := range options {
+		opt(&opts)
+	}
+	child := newHandlerContainer[Request, Response](&opts)
+	child.parent = parent
+	return child
+}
@@ -0,0 +1,80 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChildContainer(t *testing.T) {
+	parent := mutableware.NewHandlerContainer[string, string]()
+	parent.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request + ">base", nil
+		})
+
+	child := mutableware.NewChildContainer(parent)
+	child.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return next(ctx, request+">tenant")
+		})
+
+	resp, err := child.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "req>tenant>base", resp)
+
+	resp, err = parent.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "req>base", resp)
+
+	// parent changes are seen by the child.
+	parent.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return next(ctx, request+">audit")
+		})
+	resp, err = child.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "req>tenant>audit>base", resp)
+}
+
+func TestChildContainerHandleNext(t *testing.T) {
+	parent := mutableware.NewHandlerContainer[string, string]()
+	parent.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return next(ctx, request+">base")
+		})
+	child := mutableware.NewChildContainer(parent)
+
+	resp, err := child.HandleNext(context.Background(), "req", func(ctx context.Context, request string) (string, error) {
+		return request + "|end", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "req>base|end", resp)
+}
+
+func TestChildContainerObservers(t *testing.T) {
+	parentObservers := []*recordingObserver{{}, {}, {}}
+	parent := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionObserver(parentObservers[0]),
+		mutableware.ContainerOptionObserver(parentObservers[1]),
+		mutableware.ContainerOptionObserver(parentObservers[2]))
+
+	observerA := &recordingObserver{}
+	childA := mutableware.NewChildContainer(parent, mutableware.ContainerOptionObserver(observerA))
+	observerB := &recordingObserver{}
+	childB := mutableware.NewChildContainer(parent, mutableware.ContainerOptionObserver(observerB))
+	childA.AddAnonymousHandler(nil, mutableware.AddOptionName("a"))
+	childB.AddAnonymousHandler(nil, mutableware.AddOptionName("b"))
+
+	_, err := childA.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, []string{"start a", "end a false"}, observerA.events)
+	require.Empty(t, observerB.events)
+
+	_, err = childB.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, []string{"start b", "end b false"}, observerB.events)
+	require.Equal(t, []string{"start a", "end a false"}, observerA.events)
+}
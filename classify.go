@@ -0,0 +1,86 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+)
+
+// Retryable is an optional interface for errors that know whether the
+// operation that caused them can be retried.
+type Retryable interface {
+	Retryable() bool
+}
+
+// RetryClassifier reports whether an error can be retried.
+type RetryClassifier func(err error) bool
+
+// classifiedError marks an error as retryable or permanent.
+type classifiedError struct {
+	err       error
+	retryable bool
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() error {
+	return e.err
+}
+
+func (e *classifiedError) Retryable() bool {
+	return e.retryable
+}
+
+// MarkRetryable wraps err so that IsRetryable reports it as retryable.
+func MarkRetryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{err: err, retryable: true}
+}
+
+// MarkPermanent wraps err so that IsRetryable reports it as permanent.
+func MarkPermanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{err: err, retryable: false}
+}
+
+func contextWithRetryClassifier(parent context.Context, classify RetryClassifier) context.Context {
+	return context.WithValue(parent, classifierCtxKey, classify)
+}
+
+// IsRetryable reports whether err can be retried.
+// This is shared by every feature that retries requests, so they all agree.
+//
+// Errors that implement Retryable decide for themselves. Otherwise, the
+// RetryClassifier of the container handling the request is used (see
+// ContainerOptionRetryClassifier). If there is none, every error is
+// retryable except ErrStop and errors caused by the context ending.
+func IsRetryable(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	var retryable Retryable
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+	if classify, ok := (ctx.Value(classifierCtxKey)).(RetryClassifier); ok {
+		return classify(err)
+	}
+	return !errors.Is(err, ErrStop) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsRetryable is like the package's IsRetryable, but uses the container's
+// RetryClassifier (see ContainerOptionRetryClassifier), for code that
+// classifies errors returned by Handle after the request is over.
+func (hc *HandlerContainer[Request, Response]) IsRetryable(ctx context.Context, err error) bool {
+	if hc.opts.classifier != nil {
+		ctx = contextWithRetryClassifier(ctx, hc.opts.classifier)
+	}
+	return IsRetryable(ctx, err)
+}
@@ -0,0 +1,55 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRetryable(t *testing.T) {
+	ctx := context.Background()
+	baseErr := fmt.Errorf("an_error")
+	require.True(t, mutableware.IsRetryable(ctx, baseErr))
+	require.False(t, mutableware.IsRetryable(ctx, nil))
+	require.False(t, mutableware.IsRetryable(ctx, context.Canceled))
+	require.False(t, mutableware.IsRetryable(ctx, mutableware.ErrStop))
+
+	permanent := mutableware.MarkPermanent(baseErr)
+	require.ErrorIs(t, permanent, baseErr)
+	require.False(t, mutableware.IsRetryable(ctx, permanent))
+	require.False(t, mutableware.IsRetryable(ctx, fmt.Errorf("wrapped: %w", permanent)))
+	require.True(t, mutableware.IsRetryable(ctx, mutableware.MarkRetryable(context.Canceled)))
+}
+
+func TestRetryClassifier(t *testing.T) {
+	permanentErr := errors.New("permanent")
+	calls := 0
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionRetryClassifier(func(err error) bool {
+			return !errors.Is(err, permanentErr)
+		}))
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			calls++
+			return "", permanentErr
+		}, mutableware.AddOptionRetry(5, nil))
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, permanentErr)
+	require.Equal(t, 1, calls)
+}
+
+func TestContainerIsRetryable(t *testing.T) {
+	errPermanent := errors.New("permanent")
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionRetryClassifier(func(err error) bool {
+			return !errors.Is(err, errPermanent)
+		}))
+	require.False(t, hc.IsRetryable(context.Background(), errPermanent))
+	require.True(t, hc.IsRetryable(context.Background(), errors.New("other")))
+	require.True(t, mutableware.IsRetryable(context.Background(), errPermanent))
+}
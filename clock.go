@@ -0,0 +1,106 @@
+package mutableware
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock tells the time for a container's time-based features, like
+// timeouts, retry backoff, and the durations reported to observers.
+// See ContainerOptionClock.
+type Clock interface {
+	Now() time.Time
+	// AfterFunc arranges for f to be called once d has passed, without
+	// blocking the caller. Calling stop before then keeps f from being
+	// called, and reports whether it did so.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+// systemClock is the Clock used by default. It tells the real time.
+type systemClock struct{}
+
+// SystemClock returns the Clock that tells the real time. It's the default
+// wherever a Clock can be given.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// clockTimer returns a channel that's closed once d has passed.
+func clockTimer(clock Clock, d time.Duration) (<-chan struct{}, func() bool) {
+	fired := make(chan struct{})
+	stop := clock.AfterFunc(d, func() {
+		close(fired)
+	})
+	return fired, stop
+}
+
+// until returns the duration until t.
+func until(clock Clock, t time.Time) time.Duration {
+	return t.Sub(clock.Now())
+}
+
+// withTimeoutCause is context.WithTimeoutCause, measured by the clock.
+func withTimeoutCause(ctx context.Context, clock Clock, timeout time.Duration, cause error) (context.Context, context.CancelFunc) {
+	return withDeadlineCause(ctx, clock, clock.Now().Add(timeout), cause)
+}
+
+// withDeadlineCause is context.WithDeadlineCause, measured by the clock.
+func withDeadlineCause(ctx context.Context, clock Clock, deadline time.Time, cause error) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(systemClock); ok {
+		return context.WithDeadlineCause(ctx, deadline, cause)
+	}
+	if parent, ok := ctx.Deadline(); ok && parent.Before(deadline) {
+		// the parent's deadline comes first, so it's the one that matters.
+		return context.WithCancel(ctx)
+	}
+
+	inner, cancel := context.WithCancelCause(ctx)
+	c := &clockCtx{Context: inner, deadline: deadline}
+	stop := clock.AfterFunc(until(clock, deadline), func() {
+		c.mux.Lock()
+		defer c.mux.Unlock()
+		if inner.Err() != nil {
+			return
+		}
+		c.expired = true
+		if cause == nil {
+			cause = context.DeadlineExceeded
+		}
+		cancel(cause)
+	})
+	return c, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+// clockCtx is a context with a deadline measured by a Clock other than the
+// system clock.
+type clockCtx struct {
+	context.Context
+	deadline time.Time
+	mux      sync.Mutex
+	expired  bool
+}
+
+func (c *clockCtx) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *clockCtx) Err() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.expired {
+		return context.DeadlineExceeded
+	}
+	return c.Context.Err()
+}
@@ -0,0 +1,111 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestClockTimeout(t *testing.T) {
+	clock := mutablewaretest.NewFakeClock(epoch)
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionClock(clock),
+		mutableware.ContainerOptionNoErrorWrap())
+	deadline := make(chan time.Time, 1)
+	id := hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		d, _ := ctx.Deadline()
+		deadline <- d
+		<-ctx.Done()
+		return "", ctx.Err()
+	}, mutableware.AddOptionTimeout(time.Hour))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := hc.Handle(context.Background(), "a")
+		done <- err
+	}()
+	require.Equal(t, epoch.Add(time.Hour), <-deadline)
+	require.True(t, clock.WaitForTimers(1, time.Second))
+
+	clock.Advance(time.Hour - time.Second)
+	select {
+	case err := <-done:
+		require.FailNow(t, "timed out early", err)
+	default:
+	}
+
+	clock.Advance(time.Second)
+	err := <-done
+	var timeoutErr *mutableware.TimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	require.Equal(t, id, timeoutErr.Info.ID)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClockDefaultTimeout(t *testing.T) {
+	clock := mutablewaretest.NewFakeClock(epoch)
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionClock(clock),
+		mutableware.ContainerOptionDefaultTimeout(time.Minute),
+		mutableware.ContainerOptionNoErrorWrap())
+	hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		require.Equal(t, epoch.Add(time.Minute), deadline)
+		clock.Advance(time.Minute)
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+
+	_, err := hc.Handle(context.Background(), "a")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 0, clock.Timers())
+}
+
+func TestClockRetryBackoff(t *testing.T) {
+	clock := mutablewaretest.NewFakeClock(epoch)
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionClock(clock))
+	attempts := 0
+	hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		attempts++
+		if attempts < 3 {
+			return "", errors.New("flaky")
+		}
+		return "ok", nil
+	}, mutableware.AddOptionRetry(3, mutableware.ConstantBackoff(time.Hour)))
+
+	done := make(chan string, 1)
+	go func() {
+		response, _ := hc.Handle(context.Background(), "a")
+		done <- response
+	}()
+	for i := 0; i < 2; i++ {
+		require.True(t, clock.WaitForTimers(1, time.Second))
+		clock.Advance(time.Hour)
+	}
+	require.Equal(t, "ok", <-done)
+	require.Equal(t, epoch.Add(2*time.Hour), clock.Now())
+}
+
+func TestClockStats(t *testing.T) {
+	clock := mutablewaretest.NewFakeClock(epoch)
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionClock(clock),
+		mutableware.ContainerOptionStats())
+	id := hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		clock.Advance(5 * time.Second)
+		return request, nil
+	})
+
+	_, err := hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, hc.Stats()[id].LastLatency)
+	require.Equal(t, epoch, hc.ChainStats().LastMutation)
+}
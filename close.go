@@ -0,0 +1,86 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClosed is returned by Handle after the container has been closed.
+var ErrClosed = errors.New("containerClosed")
+
+// Closer is an optional interface for Handlers.
+// Close releases anything the handler holds. It's called when the handler
+// is removed from its container or swapped out, and by
+// HandlerContainer.Close once no requests are in flight.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// Close stops the container from accepting new requests, waits for the
+// requests that are in flight to finish, and then closes every handler in
+// the container that implements Closer, newest first. Errors from the
+// handlers are joined together.
+//
+// If ctx is done before the requests finish, Close returns the context's
+// error without closing the handlers. Close can be called again to keep
+// waiting. Once the handlers have been closed, Close does nothing.
+func (hc *HandlerContainer[Request, Response]) Close(ctx context.Context) error {
+	hc.closed.Store(true)
+	if hc.inflight.Load() == 0 {
+		hc.signalDrained()
+	}
+
+	select {
+	case <-hc.drained:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	hc.mux.Lock()
+	if hc.tornDown {
+		hc.mux.Unlock()
+		return nil
+	}
+	hc.tornDown = true
+	handlers := make([]identifiedHandler[Request, Response], 0, len(hc.bottom)+len(hc.stack)+len(hc.pinned)+len(hc.fallbacks)+len(hc.fastPaths))
+	handlers = append(handlers, hc.fallbacks...)
+	handlers = append(handlers, hc.bottom...)
+	handlers = append(handlers, hc.stack...)
+	handlers = append(handlers, hc.pinned...)
+	for _, handler := range hc.fastPaths {
+		handlers = append(handlers, handler.identifiedHandler)
+	}
+	hc.mux.Unlock()
+
+	var errs []error
+	for i := len(handlers) - 1; i >= 0; i-- {
+		if handlers[i].closer != nil {
+			errs = append(errs, handlers[i].closer.Close(ctx))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// enter records that a request is in flight. It returns false if the
+// container is closed, in which case the request must not be handled.
+func (hc *HandlerContainer[Request, Response]) enter() bool {
+	hc.inflight.Add(1)
+	if hc.closed.Load() {
+		hc.exit()
+		return false
+	}
+	return true
+}
+
+// exit records that a request that entered has finished.
+func (hc *HandlerContainer[Request, Response]) exit() {
+	if hc.inflight.Add(-1) == 0 && hc.closed.Load() {
+		hc.signalDrained()
+	}
+}
+
+func (hc *HandlerContainer[Request, Response]) signalDrained() {
+	hc.drainOnce.Do(func() {
+		close(hc.drained)
+	})
+}
@@ -0,0 +1,64 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+type closingHandler struct {
+	name   string
+	closed *[]string
+	err    error
+}
+
+func (h *closingHandler) Handle(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+	return next(ctx, request)
+}
+
+func (h *closingHandler) Close(ctx context.Context) error {
+	*h.closed = append(*h.closed, h.name)
+	return h.err
+}
+
+func TestClose(t *testing.T) {
+	closed := []string{}
+	failure := errors.New("failed")
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.Add(&closingHandler{name: "first", closed: &closed})
+	hc.AddAnonymousHandler(nil)
+	hc.Add(&closingHandler{name: "second", closed: &closed, err: failure})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			close(started)
+			<-release
+			return "slow", nil
+		})
+	result := hc.HandleAsync(context.Background(), "req")
+	<-started
+
+	// the request in flight keeps Close from finishing.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, hc.Close(ctx), context.DeadlineExceeded)
+	require.Empty(t, closed)
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, mutableware.ErrClosed)
+
+	close(release)
+	require.Equal(t, "slow", (<-result).Response)
+
+	require.ErrorIs(t, hc.Close(context.Background()), failure)
+	require.Equal(t, []string{"second", "first"}, closed)
+
+	require.NoError(t, hc.Close(context.Background()))
+	require.Len(t, closed, 2)
+}
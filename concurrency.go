@@ -0,0 +1,116 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrBusy is returned when a request can't wait for a concurrency limit,
+// because too many requests are waiting already.
+// See AddOptionMaxConcurrency and ContainerOptionMaxConcurrentHandles.
+var ErrBusy = errors.New("busy")
+
+// ErrQueueFull is returned by a container with a concurrency limit when its
+// queue stays full for longer than the request can wait.
+// See ContainerOptionQueueWait. It matches ErrBusy.
+var ErrQueueFull = fmt.Errorf("queueFull: %w", ErrBusy)
+
+// limiter caps how many requests can do something at the same time.
+type limiter struct {
+	slots chan struct{}
+	// queue holds a token for each request that's waiting for a slot.
+	// It's nil if any number of requests can wait.
+	queue chan struct{}
+	// full is returned when the queue is full.
+	full error
+	// queueWait is how long a request waits for room in the queue.
+	// Negative values mean it waits until its context is done.
+	queueWait time.Duration
+	clock     Clock
+}
+
+func newLimiter(n int, queue int, clock Clock) *limiter {
+	l := &limiter{
+		slots: make(chan struct{}, max(n, 1)),
+		full:  ErrBusy,
+		clock: clock,
+	}
+	if queue >= 0 {
+		l.queue = make(chan struct{}, queue)
+	}
+	return l
+}
+
+// acquire waits for a slot. Call release once the slot isn't needed.
+func (l *limiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if l.queue != nil {
+		entered, err := l.enterQueue(ctx)
+		if err != nil || !entered {
+			return err
+		}
+		defer func() { <-l.queue }()
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// enterQueue waits for room in the queue. If a slot opens up first, it's
+// taken instead, and entered is false.
+func (l *limiter) enterQueue(ctx context.Context) (entered bool, err error) {
+	select {
+	case l.queue <- struct{}{}:
+		return true, nil
+	default:
+	}
+	if l.queueWait == 0 {
+		return false, l.full
+	}
+
+	var timeout <-chan struct{}
+	if l.queueWait > 0 {
+		waited, stop := clockTimer(l.clock, l.queueWait)
+		defer stop()
+		timeout = waited
+	}
+	select {
+	case l.queue <- struct{}{}:
+		return true, nil
+	case l.slots <- struct{}{}:
+		return false, nil
+	case <-timeout:
+		return false, l.full
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+func (l *limiter) release() {
+	<-l.slots
+}
+
+// concurrencyHandler caps how many requests can run a handler at once.
+type concurrencyHandler[Request any, Response any] struct {
+	Handler[Request, Response]
+	limiter *limiter
+}
+
+func (c *concurrencyHandler[Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	if err := c.limiter.acquire(ctx); err != nil {
+		var zero Response
+		return zero, err
+	}
+	defer c.limiter.release()
+	return c.Handler.Handle(ctx, request, next)
+}
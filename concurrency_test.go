@@ -0,0 +1,144 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxConcurrency(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			started <- struct{}{}
+			<-release
+			return request, nil
+		}, mutableware.AddOptionMaxConcurrency(2, 1))
+
+	first := hc.HandleAsync(context.Background(), "first")
+	second := hc.HandleAsync(context.Background(), "second")
+	<-started
+	<-started
+
+	// the third request waits for a slot, and there's no room for a fourth.
+	third := hc.HandleAsync(context.Background(), "third")
+	require.Eventually(t, func() bool {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		_, err := hc.Handle(ctx, "fourth")
+		return errors.Is(err, mutableware.ErrBusy)
+	}, time.Second, time.Millisecond)
+	require.Empty(t, started)
+
+	close(release)
+	require.Equal(t, "first", (<-first).Response)
+	require.Equal(t, "second", (<-second).Response)
+	require.Equal(t, "third", (<-third).Response)
+}
+
+func TestMaxConcurrencyWaitCancelled(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			close(started)
+			<-release
+			return request, nil
+		}, mutableware.AddOptionMaxConcurrency(1, -1))
+
+	first := hc.HandleAsync(context.Background(), "first")
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := hc.Handle(ctx, "second")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	require.Equal(t, "first", (<-first).Response)
+}
+
+func TestMaxConcurrentHandles(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionMaxConcurrentHandles(1, 0))
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			started <- struct{}{}
+			<-release
+			return request, nil
+		})
+	// the limit covers the whole chain, not just one handler.
+	hc.AddAnonymousHandler(nil)
+
+	first := hc.HandleAsync(context.Background(), "first")
+	<-started
+	_, err := hc.Handle(context.Background(), "second")
+	require.ErrorIs(t, err, mutableware.ErrBusy)
+
+	close(release)
+	require.Equal(t, "first", (<-first).Response)
+	resp, err := hc.Handle(context.Background(), "third")
+	require.NoError(t, err)
+	require.Equal(t, "third", resp)
+}
+
+func TestQueueWait(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionMaxConcurrentHandles(1, 1),
+		mutableware.ContainerOptionQueueWait(10*time.Millisecond))
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			started <- struct{}{}
+			<-release
+			return request, nil
+		})
+
+	first := hc.HandleAsync(context.Background(), "first")
+	<-started
+	second := hc.HandleAsync(context.Background(), "second")
+
+	// once the second request is queued, the third times out waiting for room.
+	require.Eventually(t, func() bool {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err := hc.Handle(ctx, "third")
+		return errors.Is(err, mutableware.ErrQueueFull)
+	}, time.Second, time.Millisecond)
+	_, err := hc.Handle(context.Background(), "third")
+	require.ErrorIs(t, err, mutableware.ErrBusy)
+
+	close(release)
+	require.Equal(t, "first", (<-first).Response)
+	require.Equal(t, "second", (<-second).Response)
+}
+
+func TestQueueWaitBlock(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionMaxConcurrentHandles(1, 0),
+		mutableware.ContainerOptionQueueWait(-1))
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			started <- struct{}{}
+			<-release
+			return request, nil
+		})
+
+	first := hc.HandleAsync(context.Background(), "first")
+	<-started
+	second := hc.HandleAsync(context.Background(), "second")
+
+	close(release)
+	require.Equal(t, "first", (<-first).Response)
+	require.Equal(t, "second", (<-second).Response)
+}
@@ -0,0 +1,61 @@
+package mutableware
+
+import (
+	"context"
+	"sync"
+)
+
+// RequestResult pairs a request with the Result of handling it.
+type RequestResult[Request any, Response any] struct {
+	Request Request
+	Result[Response]
+}
+
+// Consume reads requests from the requests channel and runs Handle on each of
+// them. Results are sent to the returned channel, which is closed once the
+// requests channel is closed and all pending requests are done, or once ctx
+// is done.
+//
+// Use BatchOptionConcurrency to handle more than one request at a time.
+// Results are only guaranteed to be in the same order as their requests
+// when requests are handled one at a time.
+func (hc *HandlerContainer[Request, Response]) Consume(ctx context.Context, requests <-chan Request, options ...BatchOption) <-chan RequestResult[Request, Response] {
+	batchOpts := buildBatchOptions(options)
+	workers := max(batchOpts.concurrency, 1)
+
+	out := make(chan RequestResult[Request, Response], workers)
+
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case request, ok := <-requests:
+					if !ok {
+						return
+					}
+					response, err := hc.Handle(ctx, request)
+					select {
+					case <-ctx.Done():
+						return
+					case out <- RequestResult[Request, Response]{
+						Request: request,
+						Result:  Result[Response]{Response: response, Err: err},
+					}:
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
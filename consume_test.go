@@ -0,0 +1,60 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsume(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			return request * 2, nil
+		})
+
+	requests := make(chan int)
+	go func() {
+		defer close(requests)
+		for i := 0; i < 10; i++ {
+			requests <- i
+		}
+	}()
+
+	seen := map[int]int{}
+	for result := range hc.Consume(context.Background(), requests, mutableware.BatchOptionConcurrency(3)) {
+		require.NoError(t, result.Err)
+		seen[result.Request] = result.Response
+	}
+	require.Len(t, seen, 10)
+	for req, resp := range seen {
+		require.Equal(t, req*2, resp)
+	}
+}
+
+func TestConsumeOrdered(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, int]()
+	requests := make(chan int, 5)
+	for i := 0; i < 5; i++ {
+		requests <- i
+	}
+	close(requests)
+
+	order := []int{}
+	for result := range hc.Consume(context.Background(), requests) {
+		order = append(order, result.Request)
+	}
+	require.Equal(t, []int{0, 1, 2, 3, 4}, order)
+}
+
+func TestConsumeCancel(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	requests := make(chan int)
+	results := hc.Consume(ctx, requests)
+	cancel()
+	for range results {
+	}
+}
@@ -0,0 +1,347 @@
+package mutableware
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type builtContainerOptions struct {
+	name           string
+	firstID        HandlerID
+	bestEffort     bool
+	liveChain      bool
+	errorDecorator ErrorDecorator
+	recovery       bool
+	classifier     RetryClassifier
+	validate       any
+	registry       *Registry
+	skipRegistered []string
+	noHandlerInfo  bool
+	maxHandlers    int
+	rejectPaused   bool
+	timeout        time.Duration
+	maxHandles     int
+	handlesQueue   int
+	queueWait      time.Duration
+	observers      []Observer
+	stats          bool
+	expvarName     string
+	profilerLabels bool
+	profilerName   string
+	logger         *slog.Logger
+	clock          Clock
+	debug          *debugOptions
+	noHandlersErr  bool
+	defaultResp    any
+	bottom         any
+}
+
+// ContainerOption is an option for the NewHandlerContainer(...) function.
+type ContainerOption func(*builtContainerOptions)
+
+// ContainerOptionName names the container, to aid in debugging. The name
+// is included in the lines logged for ContainerOptionLogger, and is
+// returned by Name.
+func ContainerOptionName(name string) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.name = name
+	}
+}
+
+// ContainerOptionFirstID sets the HandlerID given to the first handler that
+// is added to the container. Later handlers count up from it. Giving each
+// container a different range makes it easier to tell their handlers
+// apart. Values less than 1 are ignored.
+func ContainerOptionFirstID(id HandlerID) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.firstID = id
+	}
+}
+
+// ContainerOptionBestEffort makes handler errors non-fatal.
+// When a handler returns an error without calling next, the rest of the
+// chain is still run. Every handler error is joined into the error that
+// Handle returns.
+func ContainerOptionBestEffort() ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.bestEffort = true
+	}
+}
+
+// ContainerOptionLiveChain makes each handler's next function look up the
+// following handler in the chain as it is right now, instead of using the
+// chain as it was when Handle was called. Handlers that are added or removed
+// while a request is in flight will be seen by the rest of that request.
+// The container isn't locked while handlers run, so handlers can also
+// mutate the container they're in.
+func ContainerOptionLiveChain() ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.liveChain = true
+	}
+}
+
+// ErrorDecorator wraps an error returned by a handler.
+type ErrorDecorator func(info HandlerInfo, err error) error
+
+// ContainerOptionErrorDecorator replaces the default HandleError wrapping of
+// handler errors with decorate.
+// An error is only decorated by the handler that first returned it; handlers
+// that pass along the exact error they got from next won't decorate it again.
+func ContainerOptionErrorDecorator(decorate ErrorDecorator) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.errorDecorator = decorate
+	}
+}
+
+// ContainerOptionNoErrorWrap makes Handle return handler errors verbatim,
+// instead of wrapping them in a HandleError.
+func ContainerOptionNoErrorWrap() ContainerOption {
+	return ContainerOptionErrorDecorator(func(info HandlerInfo, err error) error {
+		return err
+	})
+}
+
+// ContainerOptionRecovery converts panics in handlers into errors.
+// The error returned by the handler will be a *PanicError.
+func ContainerOptionRecovery() ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.recovery = true
+	}
+}
+
+// ContainerOptionRetryClassifier sets the function used by IsRetryable to
+// classify errors for requests handled by this container.
+func ContainerOptionRetryClassifier(classify RetryClassifier) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.classifier = classify
+	}
+}
+
+// ContainerOptionValidateResponse checks every successful response before
+// Handle returns it, no matter which handler produced it. If validate returns
+// an error, Handle fails with an error wrapping both ErrInvalidResponse and
+// the validation error.
+// If validate is nil, responses that implement a Validate() error method are
+// checked with it instead.
+// The Response type must match the container's, or NewHandlerContainer
+// panics.
+func ContainerOptionValidateResponse[Response any](validate func(Response) error) ContainerOption {
+	return func(o *builtContainerOptions) {
+		if validate == nil {
+			validate = validateMethod[Response]
+		}
+		o.validate = validate
+	}
+}
+
+// ContainerOptionRegistry adds the handlers in reg to the container when
+// it's created. Handlers registered later aren't added.
+func ContainerOptionRegistry(reg *Registry) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.registry = reg
+	}
+}
+
+// ContainerOptionSkipRegistered stops the registered handlers with the
+// names from being added to the container. See ContainerOptionRegistry.
+func ContainerOptionSkipRegistered(names ...string) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.skipRegistered = append(o.skipRegistered, names...)
+	}
+}
+
+// ContainerOptionWithoutHandlerInfo stops the container from adding its
+// handlers to the context passed to them, which saves an allocation per
+// handler per request. GetHandlerInfoFromContext won't include the
+// container's handlers, and neither will the Path of a HandleError.
+// With this option, a chain of zero or one handlers is handled without
+// allocating.
+func ContainerOptionWithoutHandlerInfo() ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.noHandlerInfo = true
+	}
+}
+
+// ContainerOptionMaxHandlers limits the number of handlers the container
+// can hold, including fallback, fast path, and registered handlers.
+// Once the container is full, Add returns 0 and TryAdd returns
+// ErrTooManyHandlers. Values less than 1 mean there is no limit.
+func ContainerOptionMaxHandlers(n int) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.maxHandlers = n
+	}
+}
+
+// ContainerOptionNoHandlersError makes Handle return ErrNoHandlers when a
+// request falls through every handler, including when the container is
+// empty, instead of the zero Response and a nil error.
+// Requests sent with HandleNext fall through to their next function as
+// usual, and child containers fall through to their parent.
+func ContainerOptionNoHandlersError() ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.noHandlersErr = true
+	}
+}
+
+// ContainerOptionDefaultResponse calls respond when a request falls through
+// every handler, including when the container is empty, and Handle returns
+// its result instead of the zero Response. It takes precedence over
+// ContainerOptionNoHandlersError.
+// Requests sent with HandleNext fall through to their next function as
+// usual, and child containers fall through to their parent.
+// The Request and Response types must match the container's, or
+// NewHandlerContainer panics.
+func ContainerOptionDefaultResponse[Request any, Response any](respond func(ctx context.Context, request Request) (Response, error)) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.defaultResp = respond
+	}
+}
+
+// ContainerOptionTerminalHandler puts handler at the bottom of the chain,
+// where it handles the requests that every other handler passes on. It
+// always stays at the bottom, no matter how the chain is changed, and it
+// can't be removed. Its next function falls through the way the chain
+// would without it. It's named "terminal", and it's closed with the
+// container if it implements Closer. Its HandlerID is the largest one
+// there is, so it doesn't take one from ContainerOptionFirstID's range.
+// The Request and Response types must match the container's, or
+// NewHandlerContainer panics.
+func ContainerOptionTerminalHandler[Request any, Response any](handler Handler[Request, Response]) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.bottom = handler
+	}
+}
+
+// ContainerOptionRejectWhilePaused makes Handle fail with ErrPaused while
+// the container is paused, instead of waiting for it to be resumed.
+// See HandlerContainer.Pause.
+func ContainerOptionRejectWhilePaused() ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.rejectPaused = true
+	}
+}
+
+// ContainerOptionDefaultTimeout gives each request a deadline of d from when
+// Handle is called, if its context doesn't already have one.
+// Values less than 1 mean there is no default.
+func ContainerOptionDefaultTimeout(d time.Duration) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.timeout = d
+	}
+}
+
+// ContainerOptionMaxConcurrentHandles limits the number of requests that the
+// container handles at the same time. Up to queue requests wait for their
+// turn until their context is done; any more fail with ErrQueueFull. Use a
+// queue of 0 to fail fast, or a negative queue to let every request wait.
+// See ContainerOptionQueueWait to make requests wait for room in the queue.
+// Handlers that send requests back into their own container can deadlock
+// if every slot is taken.
+func ContainerOptionMaxConcurrentHandles(n int, queue int) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.maxHandles = n
+		o.handlesQueue = queue
+	}
+}
+
+// ContainerOptionQueueWait sets how long a request waits for room in the
+// queue of ContainerOptionMaxConcurrentHandles before failing with
+// ErrQueueFull. By default it fails right away. Negative values mean it
+// waits until its context is done.
+func ContainerOptionQueueWait(d time.Duration) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.queueWait = d
+	}
+}
+
+// ContainerOptionObserver attaches an Observer to every handler in the
+// container. It can be given more than once to attach several observers.
+func ContainerOptionObserver(observer Observer) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.observers = append(o.observers, observer)
+	}
+}
+
+// ContainerOptionStats makes the container record how each of its handlers
+// has performed. See HandlerContainer.Stats.
+func ContainerOptionStats() ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.stats = true
+	}
+}
+
+// ContainerOptionExpvar publishes the state of the container with expvar
+// under the name: its ChainStats, how many requests are in flight, whether
+// it's paused or closed, and the stats of each handler.
+// It implies ContainerOptionStats. Like expvar.Publish, creating the
+// container panics if the name is already in use.
+func ContainerOptionExpvar(name string) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.stats = true
+		o.expvarName = name
+	}
+}
+
+// ContainerOptionProfilerLabels applies pprof labels while each handler
+// runs, so profiles attribute time to the handler that spent it. The labels
+// are "mutableware.container", set to name, and "mutableware.handler", set
+// to the handler's HandlerInfo. Labels of handlers further down the chain
+// replace them until next returns.
+func ContainerOptionProfilerLabels(name string) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.profilerLabels = true
+		o.profilerName = name
+	}
+}
+
+// ContainerOptionLogger logs every handler that is added, swapped, or
+// removed to logger at the Info level, along with the options it was added
+// with and the number of handlers left in the container. Adds that fail are
+// logged at the Warn level.
+func ContainerOptionLogger(logger *slog.Logger) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.logger = logger
+	}
+}
+
+// ContainerOptionClock replaces the real time with clock for the
+// container's timeouts, retry backoff, queue waits, deadline budgets and
+// fast paths, and for the durations it reports. Deadlines set by the
+// container are measured by clock, so contexts passed to Handle should only
+// have deadlines that were also set by clock.
+// This is mostly useful in tests, to advance time without sleeping.
+func ContainerOptionClock(clock Clock) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.clock = clock
+	}
+}
+
+// ContainerOptionDebug checks for usage that is allowed, but is likely to
+// cause hard to reproduce bugs. Each DebugIssue that is found is passed to
+// onIssue once the container's lock is released, or the container panics
+// with it if onIssue is nil. This adds overhead to every mutation, so it's
+// meant for development and tests.
+//
+// Changes made to the container while requests are in flight are reported
+// if the container has a live chain (see ContainerOptionLiveChain).
+// Holding the container's lock for longer than maxLockHold, like with a slow
+// Init (see Initializer), is reported if maxLockHold is positive.
+func ContainerOptionDebug(maxLockHold time.Duration, onIssue func(*DebugIssue)) ContainerOption {
+	return func(o *builtContainerOptions) {
+		o.debug = &debugOptions{
+			maxLockHold: maxLockHold,
+			onIssue:     onIssue,
+		}
+	}
+}
+
+func buildContainerOptions(opts []ContainerOption) *builtContainerOptions {
+	built := &builtContainerOptions{
+		clock: systemClock{},
+	}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
@@ -0,0 +1,89 @@
+// Package bulkhead provides a handler that limits how many requests can be
+// in the rest of the chain at once.
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// ErrFull is returned when a request is rejected because the bulkhead and
+// its wait queue are full.
+var ErrFull = errors.New("bulkheadFull")
+
+type builtOptions struct {
+	queue int
+}
+
+// Option is an option for the New(...) function.
+type Option func(*builtOptions)
+
+// OptionQueue lets up to n requests wait for a free slot instead of failing
+// with ErrFull right away. Waiting requests get slots in the order they
+// arrived, and still fail if their context ends.
+func OptionQueue(n int) Option {
+	return func(o *builtOptions) {
+		o.queue = n
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+type bulkhead[Request any, Response any] struct {
+	slots   chan struct{}
+	queue   int64
+	waiting atomic.Int64
+}
+
+// New creates a Handler that lets at most limit requests run the rest of the
+// chain at the same time. Requests over the limit fail with ErrFull, unless
+// OptionQueue gives them somewhere to wait.
+func New[Request any, Response any](limit int, options ...Option) mutableware.Handler[Request, Response] {
+	opts := buildOptions(options)
+	return &bulkhead[Request, Response]{
+		slots: make(chan struct{}, max(limit, 1)),
+		queue: int64(max(opts.queue, 0)),
+	}
+}
+
+func (b *bulkhead[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	if err := b.acquire(ctx); err != nil {
+		var zero Response
+		return zero, err
+	}
+	defer func() { <-b.slots }()
+	return next(ctx, request)
+}
+
+// acquire takes a slot, waiting in the queue if there's room. A request only
+// takes a free slot straight away when nobody is queued, so new arrivals
+// can't cut in front of requests that are already waiting.
+func (b *bulkhead[Request, Response]) acquire(ctx context.Context) error {
+	if b.waiting.Load() == 0 {
+		select {
+		case b.slots <- struct{}{}:
+			return nil
+		default:
+		}
+	}
+	if b.waiting.Add(1) > b.queue {
+		b.waiting.Add(-1)
+		return ErrFull
+	}
+	defer b.waiting.Add(-1)
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,146 @@
+package bulkhead_test
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/bulkhead"
+	"github.com/stretchr/testify/require"
+)
+
+func blockingContainer(release chan struct{}, started chan struct{}, options ...bulkhead.Option) *mutableware.HandlerContainer[int, int] {
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			started <- struct{}{}
+			<-release
+			return request, nil
+		})
+	hc.Add(bulkhead.New[int, int](1, options...))
+	return hc
+}
+
+func TestBulkhead(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	hc := blockingContainer(release, started)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := hc.Handle(context.Background(), 1)
+		require.NoError(t, err)
+	}()
+	<-started
+
+	_, err := hc.Handle(context.Background(), 2)
+	require.ErrorIs(t, err, bulkhead.ErrFull)
+
+	close(release)
+	wg.Wait()
+
+	resp, err := hc.Handle(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, resp)
+}
+
+func TestBulkheadQueue(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	hc := blockingContainer(release, started, bulkhead.OptionQueue(1))
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := hc.Handle(context.Background(), 1)
+			require.NoError(t, err)
+		}()
+	}
+	<-started
+	require.Eventually(t, func() bool { return queued() == 1 }, time.Second, time.Millisecond)
+
+	_, err := hc.Handle(context.Background(), 2)
+	require.ErrorIs(t, err, bulkhead.ErrFull)
+
+	close(release)
+	wg.Wait()
+}
+
+func TestBulkheadQueueContext(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	hc := blockingContainer(release, started, bulkhead.OptionQueue(1))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := hc.Handle(context.Background(), 1)
+		require.NoError(t, err)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := hc.Handle(ctx, 2)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	<-done
+}
+
+// queued counts the requests waiting in a bulkhead's queue.
+func queued() int {
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+	n := 0
+	for _, g := range strings.Split(string(buf), "\n\n") {
+		if strings.Contains(g, "bulkhead[...]).acquire(") && strings.HasPrefix(g[strings.Index(g, "["):], "[select") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestBulkheadQueueOrder(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan int, 3)
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			started <- request
+			<-release
+			return request, nil
+		})
+	hc.Add(bulkhead.New[int, int](1, bulkhead.OptionQueue(2)))
+
+	wg := sync.WaitGroup{}
+	handle := func(request int) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := hc.Handle(context.Background(), request)
+			require.NoError(t, err)
+		}()
+	}
+	handle(1)
+	require.Equal(t, 1, <-started)
+	handle(2)
+	require.Eventually(t, func() bool { return queued() == 1 }, time.Second, time.Millisecond)
+	handle(3)
+	require.Eventually(t, func() bool { return queued() == 2 }, time.Second, time.Millisecond)
+
+	for _, want := range []int{2, 3} {
+		release <- struct{}{}
+		require.Equal(t, want, <-started)
+	}
+	release <- struct{}{}
+	wg.Wait()
+}
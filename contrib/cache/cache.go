@@ -0,0 +1,105 @@
+// Package cache provides a handler that memoizes responses.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// Store holds cached responses.
+// Implementations must be safe for concurrent use.
+type Store[K comparable, Response any] interface {
+	// Get returns the response stored for key, if it hasn't expired.
+	Get(key K) (Response, bool)
+	// Set stores a response for key that expires after ttl.
+	Set(key K, response Response, ttl time.Duration)
+}
+
+type builtOptions struct {
+	capacity int
+	store    any
+	clock    mutableware.Clock
+}
+
+// Option is an option for the New(...) function.
+type Option func(*builtOptions)
+
+// OptionCapacity sets the capacity of the default in-memory LRU store.
+// The default is 1024.
+func OptionCapacity(n int) Option {
+	return func(o *builtOptions) {
+		o.capacity = n
+	}
+}
+
+// OptionClock makes responses in the default in-memory LRU store expire
+// according to clock instead of the real time.
+func OptionClock(clock mutableware.Clock) Option {
+	return func(o *builtOptions) {
+		o.clock = clock
+	}
+}
+
+// OptionStore replaces the default in-memory LRU store.
+// The types must match those of the handler, or New panics.
+func OptionStore[K comparable, Response any](store Store[K, Response]) Option {
+	return func(o *builtOptions) {
+		o.store = store
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{
+		capacity: 1024,
+	}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+type cacheHandler[Request any, Response any, K comparable] struct {
+	key   func(Request) K
+	ttl   time.Duration
+	store Store[K, Response]
+}
+
+// New creates a Handler that caches successful responses from the rest of
+// the chain for ttl, keyed by the value key returns for each request.
+// When there's a cached response for a request, it's returned and the rest
+// of the chain is skipped.
+func New[Request any, Response any, K comparable](key func(Request) K, ttl time.Duration, options ...Option) mutableware.Handler[Request, Response] {
+	opts := buildOptions(options)
+	store, ok := opts.store.(Store[K, Response])
+	if opts.store != nil && !ok {
+		panic(fmt.Sprintf("cache: OptionStore needs a %v, not a %T", reflect.TypeOf(&store).Elem(), opts.store))
+	}
+	if !ok {
+		if opts.clock != nil {
+			store = NewLRUWithClock[K, Response](opts.capacity, opts.clock)
+		} else {
+			store = NewLRU[K, Response](opts.capacity)
+		}
+	}
+	return &cacheHandler[Request, Response, K]{
+		key:   key,
+		ttl:   ttl,
+		store: store,
+	}
+}
+
+func (c *cacheHandler[Request, Response, K]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	key := c.key(request)
+	if response, ok := c.store.Get(key); ok {
+		return response, nil
+	}
+	response, err := next(ctx, request)
+	if err == nil {
+		c.store.Set(key, response, c.ttl)
+	}
+	return response, err
+}
@@ -0,0 +1,110 @@
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/cache"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	clock := mutablewaretest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	calls := 0
+	hc := mutableware.NewHandlerContainer[int, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, string]) (string, error) {
+			calls++
+			if request < 0 {
+				return "", fmt.Errorf("negative")
+			}
+			return fmt.Sprintf("%d-%d", request, calls), nil
+		})
+	hc.Add(cache.New[int, string](func(r int) int { return r }, time.Minute, cache.OptionClock(clock)))
+
+	resp, err := hc.Handle(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "1-1", resp)
+	resp, err = hc.Handle(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "1-1", resp)
+	require.Equal(t, 1, calls)
+
+	// errors aren't cached
+	_, err = hc.Handle(context.Background(), -1)
+	require.Error(t, err)
+	_, err = hc.Handle(context.Background(), -1)
+	require.Error(t, err)
+	require.Equal(t, 3, calls)
+
+	// expiry
+	clock.Advance(2 * time.Minute)
+	resp, err = hc.Handle(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "1-4", resp)
+}
+
+func TestLRU(t *testing.T) {
+	lru := cache.NewLRU[string, int](2)
+	lru.Set("a", 1, time.Hour)
+	lru.Set("b", 2, time.Hour)
+	_, ok := lru.Get("a")
+	require.True(t, ok)
+	lru.Set("c", 3, time.Hour)
+
+	_, ok = lru.Get("b")
+	require.False(t, ok)
+	v, ok := lru.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+	require.Equal(t, 2, lru.Len())
+}
+
+func TestCustomStore(t *testing.T) {
+	store := cache.NewLRU[string, string](10)
+	store.Set("warm", "from store", time.Hour)
+
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.Add(cache.New[string, string](func(r string) string { return r }, time.Hour, cache.OptionStore[string, string](store)))
+
+	resp, err := hc.Handle(context.Background(), "warm")
+	require.NoError(t, err)
+	require.Equal(t, "from store", resp)
+}
+
+func TestStoreTypeMismatch(t *testing.T) {
+	store := cache.NewLRU[int, string](10)
+	require.Panics(t, func() {
+		cache.New[string, string](func(r string) string { return r }, time.Hour, cache.OptionStore[int, string](store))
+	})
+}
+
+func TestCacheClock(t *testing.T) {
+	clock := mutablewaretest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	calls := 0
+	hc := mutableware.NewHandlerContainer[int, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, string]) (string, error) {
+			calls++
+			return fmt.Sprintf("%d-%d", request, calls), nil
+		})
+	hc.Add(cache.New[int, string](func(r int) int { return r }, time.Hour, cache.OptionClock(clock)))
+
+	resp, err := hc.Handle(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "1-1", resp)
+
+	clock.Advance(time.Hour)
+	resp, err = hc.Handle(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "1-1", resp)
+
+	clock.Advance(time.Nanosecond)
+	resp, err = hc.Handle(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "1-2", resp)
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+type lruEntry[K comparable, Response any] struct {
+	key      K
+	response Response
+	expires  time.Time
+}
+
+// LRU is an in-memory Store that evicts the least recently used
+// response once it's full.
+type LRU[K comparable, Response any] struct {
+	capacity int
+	mux      sync.Mutex
+	order    *list.List
+	entries  map[K]*list.Element
+	now      func() time.Time
+}
+
+// NewLRU creates an LRU store that holds up to capacity responses.
+func NewLRU[K comparable, Response any](capacity int) *LRU[K, Response] {
+	return &LRU[K, Response]{
+		capacity: max(capacity, 1),
+		order:    list.New(),
+		entries:  map[K]*list.Element{},
+		now:      time.Now,
+	}
+}
+
+// NewLRUWithClock is like NewLRU, but responses expire according to clock.
+func NewLRUWithClock[K comparable, Response any](capacity int, clock mutableware.Clock) *LRU[K, Response] {
+	l := NewLRU[K, Response](capacity)
+	l.now = clock.Now
+	return l
+}
+
+func (l *LRU[K, Response]) Get(key K) (Response, bool) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	var zero Response
+	elem, ok := l.entries[key]
+	if !ok {
+		return zero, false
+	}
+	entry := elem.Value.(*lruEntry[K, Response])
+	if l.now().After(entry.expires) {
+		l.order.Remove(elem)
+		delete(l.entries, key)
+		return zero, false
+	}
+	l.order.MoveToFront(elem)
+	return entry.response, true
+}
+
+func (l *LRU[K, Response]) Set(key K, response Response, ttl time.Duration) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	entry := &lruEntry[K, Response]{
+		key:      key,
+		response: response,
+		expires:  l.now().Add(ttl),
+	}
+	if elem, ok := l.entries[key]; ok {
+		elem.Value = entry
+		l.order.MoveToFront(elem)
+		return
+	}
+	l.entries[key] = l.order.PushFront(entry)
+	for l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry[K, Response]).key)
+	}
+}
+
+// Len returns the number of stored responses, including expired ones that
+// haven't been evicted yet.
+func (l *LRU[K, Response]) Len() int {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	return l.order.Len()
+}
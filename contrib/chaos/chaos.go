@@ -0,0 +1,156 @@
+// Package chaos provides a handler that injects faults into a chain.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// ErrInjected is the error injected by OptionError when no error is given.
+var ErrInjected = errors.New("injectedFault")
+
+type builtOptions struct {
+	probability float64
+	when        any
+	err         error
+	latency     time.Duration
+	mutate      any
+	clock       mutableware.Clock
+}
+
+// Option is an option for the New(...) function.
+type Option func(*builtOptions)
+
+// OptionProbability sets the chance (0 to 1) that a request gets faults
+// injected. The default is 1.
+func OptionProbability(p float64) Option {
+	return func(o *builtOptions) {
+		o.probability = p
+	}
+}
+
+// OptionWhen only injects faults into requests that when returns true for.
+// The Request type must match the handler's, or New panics.
+func OptionWhen[Request any](when func(Request) bool) Option {
+	return func(o *builtOptions) {
+		o.when = when
+	}
+}
+
+// OptionError makes affected requests fail with err instead of continuing
+// down the chain. If err is nil, ErrInjected is used.
+func OptionError(err error) Option {
+	return func(o *builtOptions) {
+		if err == nil {
+			err = ErrInjected
+		}
+		o.err = err
+	}
+}
+
+// OptionLatency delays affected requests by d before they continue.
+func OptionLatency(d time.Duration) Option {
+	return func(o *builtOptions) {
+		o.latency = d
+	}
+}
+
+// OptionMutate replaces affected requests with the result of mutate before
+// they continue down the chain.
+// The Request type must match the handler's, or New panics.
+func OptionMutate[Request any](mutate func(Request) Request) Option {
+	return func(o *builtOptions) {
+		o.mutate = mutate
+	}
+}
+
+// OptionClock makes OptionLatency's delay follow clock instead of the real
+// time.
+func OptionClock(clock mutableware.Clock) Option {
+	return func(o *builtOptions) {
+		o.clock = clock
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{
+		probability: 1,
+		clock:       mutableware.SystemClock(),
+	}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+type chaosHandler[Request any, Response any] struct {
+	probability float64
+	when        func(Request) bool
+	err         error
+	latency     time.Duration
+	mutate      func(Request) Request
+	clock       mutableware.Clock
+}
+
+// New creates a Handler that injects faults into the requests it selects.
+// Selected requests are first delayed by OptionLatency, then either failed
+// with OptionError or changed by OptionMutate before continuing down the
+// chain. With no options, New does nothing.
+func New[Request any, Response any](options ...Option) mutableware.Handler[Request, Response] {
+	opts := buildOptions(options)
+	when, ok := opts.when.(func(Request) bool)
+	if opts.when != nil && !ok {
+		panic(fmt.Sprintf("chaos: OptionWhen needs a %T, not a %T", when, opts.when))
+	}
+	mutate, ok := opts.mutate.(func(Request) Request)
+	if opts.mutate != nil && !ok {
+		panic(fmt.Sprintf("chaos: OptionMutate needs a %T, not a %T", mutate, opts.mutate))
+	}
+	return &chaosHandler[Request, Response]{
+		probability: opts.probability,
+		when:        when,
+		err:         opts.err,
+		latency:     opts.latency,
+		mutate:      mutate,
+		clock:       opts.clock,
+	}
+}
+
+func (c *chaosHandler[Request, Response]) selected(request Request) bool {
+	if c.when != nil && !c.when(request) {
+		return false
+	}
+	return c.probability >= 1 || rand.Float64() < c.probability
+}
+
+func (c *chaosHandler[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	if !c.selected(request) {
+		return next(ctx, request)
+	}
+
+	var zero Response
+	if c.latency > 0 {
+		fired := make(chan struct{})
+		stop := c.clock.AfterFunc(c.latency, func() {
+			close(fired)
+		})
+		select {
+		case <-fired:
+		case <-ctx.Done():
+			stop()
+			return zero, ctx.Err()
+		}
+	}
+	if c.err != nil {
+		return zero, c.err
+	}
+	if c.mutate != nil {
+		request = c.mutate(request)
+	}
+	return next(ctx, request)
+}
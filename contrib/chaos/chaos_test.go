@@ -0,0 +1,82 @@
+package chaos_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/chaos"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func newContainer(options ...chaos.Option) *mutableware.HandlerContainer[string, string] {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request, nil
+		})
+	hc.Add(chaos.New[string, string](options...))
+	return hc
+}
+
+func TestChaosError(t *testing.T) {
+	hc := newContainer(
+		chaos.OptionError(nil),
+		chaos.OptionWhen(func(r string) bool { return strings.HasPrefix(r, "bad") }))
+
+	resp, err := hc.Handle(context.Background(), "good")
+	require.NoError(t, err)
+	require.Equal(t, "good", resp)
+
+	_, err = hc.Handle(context.Background(), "bad")
+	require.ErrorIs(t, err, chaos.ErrInjected)
+}
+
+func TestChaosOptionTypeMismatch(t *testing.T) {
+	require.Panics(t, func() {
+		chaos.New[string, string](chaos.OptionWhen(func(r int) bool { return true }))
+	})
+	require.Panics(t, func() {
+		chaos.New[string, string](chaos.OptionMutate(func(r int) int { return r }))
+	})
+}
+
+func TestChaosProbability(t *testing.T) {
+	hc := newContainer(chaos.OptionError(nil), chaos.OptionProbability(0))
+	for i := 0; i < 100; i++ {
+		_, err := hc.Handle(context.Background(), "x")
+		require.NoError(t, err)
+	}
+}
+
+func TestChaosLatencyAndMutate(t *testing.T) {
+	clock := mutablewaretest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	hc := newContainer(
+		chaos.OptionLatency(time.Second),
+		chaos.OptionMutate(strings.ToUpper),
+		chaos.OptionClock(clock))
+
+	done := make(chan mutableware.Result[string], 1)
+	go func() {
+		resp, err := hc.Handle(context.Background(), "x")
+		done <- mutableware.Result[string]{Response: resp, Err: err}
+	}()
+	require.True(t, clock.WaitForTimers(1, time.Second))
+	select {
+	case <-done:
+		t.Fatal("request wasn't delayed")
+	default:
+	}
+	clock.Advance(time.Second)
+	result := <-done
+	require.NoError(t, result.Err)
+	require.Equal(t, "X", result.Response)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := hc.Handle(ctx, "x")
+	require.ErrorIs(t, err, context.Canceled)
+}
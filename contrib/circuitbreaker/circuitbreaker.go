@@ -0,0 +1,267 @@
+// Package circuitbreaker provides a handler that stops sending requests to
+// the rest of the chain while it is failing.
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// ErrOpen is returned when a request is rejected because the circuit is open.
+var ErrOpen = errors.New("circuitOpen")
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// Closed lets all requests through.
+	Closed State = iota
+	// Open rejects all requests.
+	Open
+	// HalfOpen lets a single trial request through to decide whether
+	// to close the circuit again.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "halfOpen"
+	}
+	return "unknown"
+}
+
+type builtOptions struct {
+	threshold   float64
+	minRequests int
+	window      time.Duration
+	cooldown    time.Duration
+	openErr     error
+	clock       mutableware.Clock
+}
+
+// Option is an option for the New(...) function.
+type Option func(*builtOptions)
+
+// OptionThreshold sets the error rate (0 to 1) at which the circuit opens.
+// The default is 0.5.
+func OptionThreshold(rate float64) Option {
+	return func(o *builtOptions) {
+		o.threshold = rate
+	}
+}
+
+// OptionMinRequests sets how many requests must be seen in a window before
+// the error rate is considered. The default is 10.
+func OptionMinRequests(n int) Option {
+	return func(o *builtOptions) {
+		o.minRequests = n
+	}
+}
+
+// OptionWindow sets how long requests are counted before the counts are
+// reset. The default is 10 seconds.
+func OptionWindow(d time.Duration) Option {
+	return func(o *builtOptions) {
+		o.window = d
+	}
+}
+
+// OptionCooldown sets how long the circuit stays open before a trial
+// request is let through. The default is 5 seconds.
+func OptionCooldown(d time.Duration) Option {
+	return func(o *builtOptions) {
+		o.cooldown = d
+	}
+}
+
+// OptionError sets the error returned for rejected requests when there is
+// no fallback. The default is ErrOpen.
+func OptionError(err error) Option {
+	return func(o *builtOptions) {
+		o.openErr = err
+	}
+}
+
+// OptionClock makes the window and cooldown follow clock instead of the
+// real time.
+func OptionClock(clock mutableware.Clock) Option {
+	return func(o *builtOptions) {
+		o.clock = clock
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{
+		threshold:   0.5,
+		minRequests: 10,
+		window:      10 * time.Second,
+		cooldown:    5 * time.Second,
+		openErr:     ErrOpen,
+		clock:       mutableware.SystemClock(),
+	}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+// Breaker is a Handler that opens the circuit when too many requests
+// sent to the rest of the chain fail. Errors that mutableware.IsRetryable
+// reports as retryable count as failures, and so do deadlines and
+// timeouts (including *mutableware.TimeoutError). Other errors, like
+// cancellations, mutableware.ErrStop, and errors marked permanent, don't
+// indicate that the rest of the chain is unhealthy, so they don't count.
+type Breaker[Request any, Response any] struct {
+	opts     *builtOptions
+	fallback mutableware.CurriedHandlerFunc[Request, Response]
+
+	mux         sync.Mutex
+	state       State
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	trialActive bool
+}
+
+// New creates a circuit breaker. While the circuit is open, requests are
+// sent to fallback instead of the rest of the chain. If fallback is nil,
+// they fail with ErrOpen instead.
+func New[Request any, Response any](fallback mutableware.CurriedHandlerFunc[Request, Response], options ...Option) *Breaker[Request, Response] {
+	opts := buildOptions(options)
+	return &Breaker[Request, Response]{
+		opts:        opts,
+		fallback:    fallback,
+		windowStart: opts.clock.Now(),
+	}
+}
+
+// State returns the current state of the circuit.
+func (b *Breaker[Request, Response]) State() State {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.refresh(b.opts.clock.Now())
+	return b.state
+}
+
+func (b *Breaker[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	trial, ok := b.admit()
+	if !ok {
+		if b.fallback != nil {
+			return b.fallback(ctx, request)
+		}
+		var zero Response
+		return zero, b.opts.openErr
+	}
+
+	response, err := next(ctx, request)
+	b.record(trial, outcomeOf(ctx, err))
+	return response, err
+}
+
+// outcome is what a request says about the health of the rest of the chain.
+type outcome int
+
+const (
+	succeeded outcome = iota
+	failed
+	// inconclusive requests say nothing either way, like those canceled by
+	// their caller.
+	inconclusive
+)
+
+// outcomeOf classifies the error returned by the rest of the chain.
+func outcomeOf(ctx context.Context, err error) outcome {
+	switch {
+	case err == nil, errors.Is(err, mutableware.ErrStop):
+		return succeeded
+	case errors.Is(err, context.Canceled):
+		return inconclusive
+	case errors.Is(err, context.DeadlineExceeded), mutableware.IsRetryable(ctx, err):
+		return failed
+	}
+	return succeeded
+}
+
+// refresh moves the circuit to half-open once the cooldown is over and
+// resets the counts at the end of each window.
+// The lock must be held.
+func (b *Breaker[Request, Response]) refresh(now time.Time) {
+	if b.state == Open && now.Sub(b.openedAt) >= b.opts.cooldown {
+		b.state = HalfOpen
+		b.trialActive = false
+	}
+	if now.Sub(b.windowStart) >= b.opts.window {
+		b.windowStart = now
+		b.requests = 0
+		b.failures = 0
+	}
+}
+
+// admit decides whether a request may pass.
+func (b *Breaker[Request, Response]) admit() (trial bool, ok bool) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.refresh(b.opts.clock.Now())
+
+	switch b.state {
+	case Open:
+		return false, false
+	case HalfOpen:
+		if b.trialActive {
+			return false, false
+		}
+		b.trialActive = true
+		return true, true
+	}
+	return false, true
+}
+
+// record updates the circuit with the outcome of a request. An
+// inconclusive trial leaves the circuit half-open for another trial.
+func (b *Breaker[Request, Response]) record(trial bool, result outcome) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	now := b.opts.clock.Now()
+
+	if trial {
+		b.trialActive = false
+		switch result {
+		case failed:
+			b.open(now)
+		case succeeded:
+			b.state = Closed
+			b.windowStart = now
+			b.requests = 0
+			b.failures = 0
+		}
+		return
+	}
+	if b.state != Closed || result == inconclusive {
+		return
+	}
+
+	b.requests++
+	if result == failed {
+		b.failures++
+	}
+	if b.requests >= b.opts.minRequests && float64(b.failures)/float64(b.requests) >= b.opts.threshold {
+		b.open(now)
+	}
+}
+
+// open trips the circuit.
+// The lock must be held.
+func (b *Breaker[Request, Response]) open(now time.Time) {
+	b.state = Open
+	b.openedAt = now
+}
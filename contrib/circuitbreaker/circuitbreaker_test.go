@@ -0,0 +1,195 @@
+package circuitbreaker_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/circuitbreaker"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker(t *testing.T) {
+	expectedErr := fmt.Errorf("an_error")
+	failing := true
+	calls := 0
+
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			calls++
+			if failing {
+				return "", expectedErr
+			}
+			return "ok", nil
+		})
+	clock := mutablewaretest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	breaker := circuitbreaker.New[string, string](nil,
+		circuitbreaker.OptionMinRequests(4),
+		circuitbreaker.OptionThreshold(0.5),
+		circuitbreaker.OptionCooldown(time.Minute),
+		circuitbreaker.OptionClock(clock),
+	)
+	hc.Add(breaker)
+
+	for i := 0; i < 4; i++ {
+		_, err := hc.Handle(context.Background(), "req")
+		require.ErrorIs(t, err, expectedErr)
+	}
+	require.Equal(t, circuitbreaker.Open, breaker.State())
+
+	// short-circuited
+	_, err := hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, circuitbreaker.ErrOpen)
+	require.Equal(t, 4, calls)
+
+	// a failed trial opens the circuit again
+	clock.Advance(time.Minute)
+	require.Equal(t, circuitbreaker.HalfOpen, breaker.State())
+	_, err = hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, circuitbreaker.Open, breaker.State())
+
+	// a successful trial closes it
+	failing = false
+	clock.Advance(time.Minute)
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+	require.Equal(t, circuitbreaker.Closed, breaker.State())
+}
+
+func TestBreakerFallback(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "", fmt.Errorf("an_error")
+		})
+	hc.Add(circuitbreaker.New(
+		func(ctx context.Context, request string) (string, error) {
+			return "cached", nil
+		},
+		circuitbreaker.OptionMinRequests(1),
+		circuitbreaker.OptionCooldown(time.Hour),
+	))
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.Error(t, err)
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "cached", resp)
+}
+
+func TestBreakerIgnoresPermanentErrors(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "", mutableware.MarkPermanent(fmt.Errorf("bad request"))
+		})
+	breaker := circuitbreaker.New[string, string](nil, circuitbreaker.OptionMinRequests(1))
+	hc.Add(breaker)
+
+	for i := 0; i < 5; i++ {
+		_, err := hc.Handle(context.Background(), "req")
+		require.Error(t, err)
+	}
+	require.Equal(t, circuitbreaker.Closed, breaker.State())
+}
+
+func TestBreakerCountsDeadlines(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			// the backend hangs until the request gives up.
+			<-ctx.Done()
+			return "", ctx.Err()
+		})
+	breaker := circuitbreaker.New[string, string](nil, circuitbreaker.OptionMinRequests(2))
+	hc.Add(breaker)
+
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		_, err := hc.Handle(ctx, "req")
+		cancel()
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+	require.Equal(t, circuitbreaker.Open, breaker.State())
+}
+
+func TestBreakerIgnoresCancellation(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "", ctx.Err()
+		})
+	breaker := circuitbreaker.New[string, string](nil, circuitbreaker.OptionMinRequests(1))
+	hc.Add(breaker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 5; i++ {
+		_, err := hc.Handle(ctx, "req")
+		require.ErrorIs(t, err, context.Canceled)
+	}
+	require.Equal(t, circuitbreaker.Closed, breaker.State())
+}
+
+func TestBreakerUsesRetryClassifier(t *testing.T) {
+	badRequest := errors.New("bad request")
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionRetryClassifier(func(err error) bool {
+			return !errors.Is(err, badRequest)
+		}))
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "", badRequest
+		})
+	breaker := circuitbreaker.New[string, string](nil, circuitbreaker.OptionMinRequests(1))
+	hc.Add(breaker)
+
+	for i := 0; i < 5; i++ {
+		_, err := hc.Handle(context.Background(), "req")
+		require.ErrorIs(t, err, badRequest)
+	}
+	require.Equal(t, circuitbreaker.Closed, breaker.State())
+}
+
+func TestBreakerCanceledTrial(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			if err := ctx.Err(); err != nil {
+				return "", err
+			}
+			return "", fmt.Errorf("an_error")
+		})
+	clock := mutablewaretest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	breaker := circuitbreaker.New[string, string](nil,
+		circuitbreaker.OptionMinRequests(1),
+		circuitbreaker.OptionCooldown(time.Minute),
+		circuitbreaker.OptionClock(clock),
+	)
+	hc.Add(breaker)
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.Error(t, err)
+	require.Equal(t, circuitbreaker.Open, breaker.State())
+	clock.Advance(time.Minute)
+	require.Equal(t, circuitbreaker.HalfOpen, breaker.State())
+
+	// the trial's caller gave up, so the backend wasn't checked.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = hc.Handle(ctx, "req")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, circuitbreaker.HalfOpen, breaker.State())
+
+	// another trial is let through.
+	_, err = hc.Handle(context.Background(), "req")
+	require.NotErrorIs(t, err, circuitbreaker.ErrOpen)
+	require.Equal(t, circuitbreaker.Open, breaker.State())
+}
@@ -0,0 +1,112 @@
+// Package connectware adapts mutableware to connect-go interceptors.
+package connectware
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/erinpentecost/mutableware"
+)
+
+type builtOptions struct {
+	unary            *mutableware.HandlerContainer[connect.AnyRequest, connect.AnyResponse]
+	streamingClient  *mutableware.HandlerContainer[connect.Spec, connect.StreamingClientConn]
+	streamingHandler *mutableware.HandlerContainer[connect.StreamingHandlerConn, struct{}]
+}
+
+// Option is an option for the NewInterceptor(...) function.
+type Option func(*builtOptions)
+
+// OptionUnary runs unary calls through hc.
+func OptionUnary(hc *mutableware.HandlerContainer[connect.AnyRequest, connect.AnyResponse]) Option {
+	return func(o *builtOptions) {
+		o.unary = hc
+	}
+}
+
+// OptionStreamingClient runs streaming calls made by clients through hc.
+// Handlers can wrap the connect.StreamingClientConn returned by next to
+// intercept the messages that are sent and received.
+func OptionStreamingClient(hc *mutableware.HandlerContainer[connect.Spec, connect.StreamingClientConn]) Option {
+	return func(o *builtOptions) {
+		o.streamingClient = hc
+	}
+}
+
+// OptionStreamingHandler runs streaming calls received by handlers
+// through hc. Handlers can pass a wrapped connect.StreamingHandlerConn to
+// next to intercept the messages that are sent and received.
+func OptionStreamingHandler(hc *mutableware.HandlerContainer[connect.StreamingHandlerConn, struct{}]) Option {
+	return func(o *builtOptions) {
+		o.streamingHandler = hc
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+// Interceptor is a connect.Interceptor backed by containers.
+// Kinds of calls that don't have a container are passed through untouched.
+type Interceptor struct {
+	opts *builtOptions
+}
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+// NewInterceptor creates an Interceptor that runs calls through the
+// containers set by its options. When a call falls through a container's
+// chain, it continues on to the rest of the connect call.
+func NewInterceptor(options ...Option) *Interceptor {
+	return &Interceptor{
+		opts: buildOptions(options),
+	}
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	hc := i.opts.unary
+	if hc == nil {
+		return next
+	}
+	return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+		return hc.HandleNext(ctx, request, mutableware.CurriedHandlerFunc[connect.AnyRequest, connect.AnyResponse](next))
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+// If the chain fails, the returned connection fails every operation with
+// the chain's error.
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	hc := i.opts.streamingClient
+	if hc == nil {
+		return next
+	}
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn, err := hc.HandleNext(ctx, spec, func(ctx context.Context, spec connect.Spec) (connect.StreamingClientConn, error) {
+			return next(ctx, spec), nil
+		})
+		if err != nil {
+			return &errorClientConn{spec: spec, err: err}
+		}
+		return conn
+	}
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	hc := i.opts.streamingHandler
+	if hc == nil {
+		return next
+	}
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		_, err := hc.HandleNext(ctx, conn, func(ctx context.Context, conn connect.StreamingHandlerConn) (struct{}, error) {
+			return struct{}{}, next(ctx, conn)
+		})
+		return err
+	}
+}
@@ -0,0 +1,128 @@
+package connectware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/connectware"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	echoProcedure  = "/test.Echo/Echo"
+	countProcedure = "/test.Echo/Count"
+)
+
+func newServer(t *testing.T, interceptor connect.Interceptor) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.Handle(echoProcedure, connect.NewUnaryHandler(echoProcedure,
+		func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+			return connect.NewResponse(wrapperspb.String("echo " + req.Msg.Value)), nil
+		}, connect.WithInterceptors(interceptor)))
+	mux.Handle(countProcedure, connect.NewServerStreamHandler(countProcedure,
+		func(ctx context.Context, req *connect.Request[wrapperspb.Int32Value], stream *connect.ServerStream[wrapperspb.Int32Value]) error {
+			for i := int32(0); i < req.Msg.Value; i++ {
+				if err := stream.Send(wrapperspb.Int32(i)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, connect.WithInterceptors(interceptor)))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestUnary(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[connect.AnyRequest, connect.AnyResponse]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request connect.AnyRequest, next mutableware.CurriedHandlerFunc[connect.AnyRequest, connect.AnyResponse]) (connect.AnyResponse, error) {
+			if request.Header().Get("Authorization") == "" {
+				return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("no token"))
+			}
+			response, err := next(ctx, request)
+			if err == nil {
+				response.Header().Set("X-Handled", "yes")
+			}
+			return response, err
+		})
+	server := newServer(t, connectware.NewInterceptor(connectware.OptionUnary(hc)))
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](server.Client(), server.URL+echoProcedure)
+
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("hi")))
+	require.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	req := connect.NewRequest(wrapperspb.String("hi"))
+	req.Header().Set("Authorization", "token")
+	resp, err := client.CallUnary(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, "echo hi", resp.Msg.Value)
+	require.Equal(t, "yes", resp.Header().Get("X-Handled"))
+}
+
+// countingHandlerConn counts messages sent by the handler.
+type countingHandlerConn struct {
+	connect.StreamingHandlerConn
+	sent *int
+}
+
+func (c *countingHandlerConn) Send(msg any) error {
+	*c.sent++
+	return c.StreamingHandlerConn.Send(msg)
+}
+
+func TestStreaming(t *testing.T) {
+	sent := 0
+	handlers := mutableware.NewHandlerContainer[connect.StreamingHandlerConn, struct{}]()
+	handlers.AddAnonymousHandler(
+		func(ctx context.Context, conn connect.StreamingHandlerConn, next mutableware.CurriedHandlerFunc[connect.StreamingHandlerConn, struct{}]) (struct{}, error) {
+			return next(ctx, &countingHandlerConn{StreamingHandlerConn: conn, sent: &sent})
+		})
+
+	procedures := []string{}
+	clients := mutableware.NewHandlerContainer[connect.Spec, connect.StreamingClientConn]()
+	clients.AddAnonymousHandler(
+		func(ctx context.Context, spec connect.Spec, next mutableware.CurriedHandlerFunc[connect.Spec, connect.StreamingClientConn]) (connect.StreamingClientConn, error) {
+			procedures = append(procedures, spec.Procedure)
+			return next(ctx, spec)
+		})
+
+	server := newServer(t, connectware.NewInterceptor(connectware.OptionStreamingHandler(handlers)))
+	client := connect.NewClient[wrapperspb.Int32Value, wrapperspb.Int32Value](server.Client(), server.URL+countProcedure,
+		connect.WithInterceptors(connectware.NewInterceptor(connectware.OptionStreamingClient(clients))))
+
+	stream, err := client.CallServerStream(context.Background(), connect.NewRequest(wrapperspb.Int32(3)))
+	require.NoError(t, err)
+	received := 0
+	for stream.Receive() {
+		received++
+	}
+	require.NoError(t, stream.Err())
+	require.NoError(t, stream.Close())
+
+	require.Equal(t, 3, received)
+	require.Equal(t, 3, sent)
+	require.Equal(t, []string{countProcedure}, procedures)
+}
+
+func TestStreamingClientError(t *testing.T) {
+	expectedErr := connect.NewError(connect.CodeUnavailable, errors.New("down"))
+	clients := mutableware.NewHandlerContainer[connect.Spec, connect.StreamingClientConn](mutableware.ContainerOptionNoErrorWrap())
+	clients.AddAnonymousHandler(
+		func(ctx context.Context, spec connect.Spec, next mutableware.CurriedHandlerFunc[connect.Spec, connect.StreamingClientConn]) (connect.StreamingClientConn, error) {
+			return nil, expectedErr
+		})
+
+	server := newServer(t, connectware.NewInterceptor())
+	client := connect.NewClient[wrapperspb.Int32Value, wrapperspb.Int32Value](server.Client(), server.URL+countProcedure,
+		connect.WithInterceptors(connectware.NewInterceptor(connectware.OptionStreamingClient(clients))))
+
+	_, err := client.CallServerStream(context.Background(), connect.NewRequest(wrapperspb.Int32(3)))
+	require.Equal(t, connect.CodeUnavailable, connect.CodeOf(err))
+}
@@ -0,0 +1,49 @@
+package connectware
+
+import (
+	"net/http"
+
+	"connectrpc.com/connect"
+)
+
+// errorClientConn is a connect.StreamingClientConn that was never opened.
+type errorClientConn struct {
+	spec connect.Spec
+	err  error
+}
+
+func (c *errorClientConn) Spec() connect.Spec {
+	return c.spec
+}
+
+func (c *errorClientConn) Peer() connect.Peer {
+	return connect.Peer{}
+}
+
+func (c *errorClientConn) Send(any) error {
+	return c.err
+}
+
+func (c *errorClientConn) RequestHeader() http.Header {
+	return http.Header{}
+}
+
+func (c *errorClientConn) CloseRequest() error {
+	return c.err
+}
+
+func (c *errorClientConn) Receive(any) error {
+	return c.err
+}
+
+func (c *errorClientConn) ResponseHeader() http.Header {
+	return http.Header{}
+}
+
+func (c *errorClientConn) ResponseTrailer() http.Header {
+	return http.Header{}
+}
+
+func (c *errorClientConn) CloseResponse() error {
+	return c.err
+}
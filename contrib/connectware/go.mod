@@ -0,0 +1,18 @@
+module github.com/erinpentecost/mutableware/contrib/connectware
+
+go 1.21.4
+
+require (
+	connectrpc.com/connect v1.16.2
+	github.com/erinpentecost/mutableware v0.0.0
+	github.com/stretchr/testify v1.8.4
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	google.golang.org/protobuf v1.33.0
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/erinpentecost/mutableware => ../..
@@ -0,0 +1,23 @@
+module github.com/erinpentecost/mutableware/contrib/grpcware
+
+go 1.21.4
+
+require (
+	github.com/erinpentecost/mutableware v0.0.0
+	github.com/stretchr/testify v1.8.4
+	google.golang.org/grpc v1.62.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.20.0 // indirect
+	golang.org/x/sys v0.16.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240123012728-ef4313101c80 // indirect
+	google.golang.org/protobuf v1.32.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/erinpentecost/mutableware => ../..
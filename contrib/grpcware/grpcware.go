@@ -0,0 +1,76 @@
+// Package grpcware adapts mutableware to gRPC interceptors.
+package grpcware
+
+import (
+	"context"
+
+	"github.com/erinpentecost/mutableware"
+	"google.golang.org/grpc"
+)
+
+// StreamServerRequest is a streaming RPC received by a server.
+// Handlers can replace Stream with a wrapper to intercept the messages
+// that are sent and received.
+type StreamServerRequest struct {
+	Server any
+	Stream grpc.ServerStream
+	Info   *grpc.StreamServerInfo
+}
+
+// StreamClientRequest is a streaming RPC started by a client.
+type StreamClientRequest struct {
+	Desc    *grpc.StreamDesc
+	Conn    *grpc.ClientConn
+	Method  string
+	Options []grpc.CallOption
+}
+
+// serverStream replaces the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// StreamServerInterceptor runs streaming RPCs through hc.
+// When a request falls through the chain, the RPC's handler is called with
+// the request's Server and Stream. If a handler passes a new context to next,
+// the stream's Context() returns it.
+func StreamServerInterceptor(hc *mutableware.HandlerContainer[*StreamServerRequest, struct{}]) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		request := &StreamServerRequest{
+			Server: srv,
+			Stream: ss,
+			Info:   info,
+		}
+		_, err := hc.HandleNext(ss.Context(), request, func(ctx context.Context, request *StreamServerRequest) (struct{}, error) {
+			stream := request.Stream
+			if ctx != stream.Context() {
+				stream = &serverStream{ServerStream: stream, ctx: ctx}
+			}
+			return struct{}{}, handler(request.Server, stream)
+		})
+		return err
+	}
+}
+
+// StreamClientInterceptor runs streaming RPCs through hc.
+// When a request falls through the chain, the stream is opened. Handlers
+// can wrap the grpc.ClientStream returned by next to intercept the messages
+// that are sent and received.
+func StreamClientInterceptor(hc *mutableware.HandlerContainer[*StreamClientRequest, grpc.ClientStream]) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		request := &StreamClientRequest{
+			Desc:    desc,
+			Conn:    cc,
+			Method:  method,
+			Options: opts,
+		}
+		return hc.HandleNext(ctx, request, func(ctx context.Context, request *StreamClientRequest) (grpc.ClientStream, error) {
+			return streamer(ctx, request.Desc, request.Conn, request.Method, request.Options...)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package grpcware_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/grpcware"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// countingServerStream counts messages sent by the server.
+type countingServerStream struct {
+	grpc.ServerStream
+	sent *atomic.Int32
+}
+
+func (s *countingServerStream) SendMsg(m any) error {
+	s.sent.Add(1)
+	return s.ServerStream.SendMsg(m)
+}
+
+type ctxKey struct{}
+
+func TestStreamInterceptors(t *testing.T) {
+	sent := atomic.Int32{}
+	server := mutableware.NewHandlerContainer[*grpcware.StreamServerRequest, struct{}](mutableware.ContainerOptionNoErrorWrap())
+	server.AddAnonymousHandler(
+		func(ctx context.Context, request *grpcware.StreamServerRequest, next mutableware.CurriedHandlerFunc[*grpcware.StreamServerRequest, struct{}]) (struct{}, error) {
+			request.Stream = &countingServerStream{ServerStream: request.Stream, sent: &sent}
+			return next(context.WithValue(ctx, ctxKey{}, "server"), request)
+		})
+	server.AddAnonymousHandler(
+		func(ctx context.Context, request *grpcware.StreamServerRequest, next mutableware.CurriedHandlerFunc[*grpcware.StreamServerRequest, struct{}]) (struct{}, error) {
+			if request.Info.FullMethod != healthpb.Health_Watch_FullMethodName {
+				return struct{}{}, status.Error(codes.PermissionDenied, "denied")
+			}
+			return next(ctx, request)
+		})
+
+	methods := []string{}
+	client := mutableware.NewHandlerContainer[*grpcware.StreamClientRequest, grpc.ClientStream]()
+	client.AddAnonymousHandler(
+		func(ctx context.Context, request *grpcware.StreamClientRequest, next mutableware.CurriedHandlerFunc[*grpcware.StreamClientRequest, grpc.ClientStream]) (grpc.ClientStream, error) {
+			methods = append(methods, request.Method)
+			return next(ctx, request)
+		})
+
+	listener := bufconn.Listen(1 << 16)
+	srv := grpc.NewServer(grpc.StreamInterceptor(grpcware.StreamServerInterceptor(server)))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(listener) }()
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStreamInterceptor(grpcware.StreamClientInterceptor(client)))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watch, err := healthpb.NewHealthClient(conn).Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	resp, err := watch.Recv()
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	require.Equal(t, int32(1), sent.Load())
+	require.Equal(t, []string{healthpb.Health_Watch_FullMethodName}, methods)
+}
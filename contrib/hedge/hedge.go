@@ -0,0 +1,141 @@
+// Package hedge provides a handler that reduces tail latency by re-issuing
+// slow requests to the rest of the chain.
+package hedge
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+type builtOptions struct {
+	maxAttempts int
+	clock       mutableware.Clock
+}
+
+// Option is an option for the New(...) function.
+type Option func(*builtOptions)
+
+// OptionMaxAttempts sets the maximum number of concurrent attempts, including
+// the first one. The default is 2.
+func OptionMaxAttempts(n int) Option {
+	return func(o *builtOptions) {
+		o.maxAttempts = n
+	}
+}
+
+// OptionClock makes the delay before each new attempt follow clock instead
+// of the real time.
+func OptionClock(clock mutableware.Clock) Option {
+	return func(o *builtOptions) {
+		o.clock = clock
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{
+		maxAttempts: 2,
+		clock:       mutableware.SystemClock(),
+	}
+	for _, opt := range opts {
+		opt(built)
+	}
+	built.maxAttempts = max(built.maxAttempts, 1)
+	return built
+}
+
+type hedgeHandler[Request any, Response any] struct {
+	delay       time.Duration
+	maxAttempts int
+	clock       mutableware.Clock
+}
+
+// New creates a Handler that sends the request to the rest of the chain, and
+// sends it again if no response has arrived after delay. The first
+// successful response is returned and all other attempts are canceled.
+// If an attempt fails with an error that mutableware.IsRetryable reports as
+// retryable, the next attempt is started right away. Other errors are
+// returned immediately.
+//
+// Attempts run concurrently with the same request, so downstream handlers
+// must not modify it.
+func New[Request any, Response any](delay time.Duration, options ...Option) mutableware.Handler[Request, Response] {
+	opts := buildOptions(options)
+	return &hedgeHandler[Request, Response]{
+		delay:       delay,
+		maxAttempts: opts.maxAttempts,
+		clock:       opts.clock,
+	}
+}
+
+func (h *hedgeHandler[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	var zero Response
+
+	attemptCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan mutableware.Result[Response], h.maxAttempts)
+	launched := 0
+	launch := func() {
+		launched++
+		go func() {
+			response, err := next(attemptCtx, request)
+			results <- mutableware.Result[Response]{Response: response, Err: err}
+		}()
+	}
+
+	// each attempt gets a new timer, so a tick left over from an earlier
+	// one can't start the next attempt early. Once every attempt has been
+	// launched, fired is nil so the loop only waits for results.
+	var fired <-chan struct{}
+	stop := func() bool { return false }
+	arm := func() {
+		stop()
+		if launched < h.maxAttempts {
+			fired, stop = h.timer()
+		} else {
+			fired = nil
+		}
+	}
+	launch()
+	arm()
+	defer func() {
+		stop()
+	}()
+
+	errs := []error{}
+	for {
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-fired:
+			launch()
+			arm()
+		case result := <-results:
+			if result.Err == nil {
+				return result.Response, nil
+			}
+			if !mutableware.IsRetryable(ctx, result.Err) {
+				return zero, result.Err
+			}
+			errs = append(errs, result.Err)
+			if launched < h.maxAttempts {
+				launch()
+				arm()
+			} else if len(errs) == launched {
+				return zero, errors.Join(errs...)
+			}
+		}
+	}
+}
+
+// timer returns a channel that's closed once the delay has passed.
+func (h *hedgeHandler[Request, Response]) timer() (<-chan struct{}, func() bool) {
+	fired := make(chan struct{})
+	stop := h.clock.AfterFunc(h.delay, func() {
+		close(fired)
+	})
+	return fired, stop
+}
@@ -0,0 +1,126 @@
+package hedge_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/hedge"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedgeSlowFirstAttempt(t *testing.T) {
+	var calls atomic.Int32
+	hc := mutableware.NewHandlerContainer[string, int32]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int32]) (int32, error) {
+			call := calls.Add(1)
+			if call == 1 {
+				// the first attempt hangs until it's canceled
+				<-ctx.Done()
+				return 0, ctx.Err()
+			}
+			return call, nil
+		})
+	clock := mutablewaretest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	hc.Add(hedge.New[string, int32](time.Second, hedge.OptionClock(clock)))
+
+	done := make(chan mutableware.Result[int32], 1)
+	go func() {
+		resp, err := hc.Handle(context.Background(), "req")
+		done <- mutableware.Result[int32]{Response: resp, Err: err}
+	}()
+	require.True(t, clock.WaitForTimers(1, time.Second))
+	clock.Advance(time.Second)
+
+	result := <-done
+	require.NoError(t, result.Err)
+	require.Equal(t, int32(2), result.Response)
+}
+
+func TestHedgeAllFail(t *testing.T) {
+	expectedErr := fmt.Errorf("an_error")
+	var calls atomic.Int32
+	hc := mutableware.NewHandlerContainer[string, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int]) (int, error) {
+			calls.Add(1)
+			return 0, expectedErr
+		})
+	hc.Add(hedge.New[string, int](time.Hour, hedge.OptionMaxAttempts(3)))
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestHedgePermanentError(t *testing.T) {
+	expectedErr := mutableware.MarkPermanent(fmt.Errorf("an_error"))
+	var calls atomic.Int32
+	hc := mutableware.NewHandlerContainer[string, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int]) (int, error) {
+			calls.Add(1)
+			return 0, expectedErr
+		})
+	hc.Add(hedge.New[string, int](time.Hour, hedge.OptionMaxAttempts(3)))
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+// handleWaiting reports whether the goroutine running the hedge handler is
+// blocked in its select, rather than running.
+func handleWaiting() bool {
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+	for _, g := range strings.Split(string(buf), "\n\n") {
+		if strings.Contains(g, "hedgeHandler[...]).Handle(") {
+			return strings.HasPrefix(g[strings.Index(g, "["):], "[select")
+		}
+	}
+	return false
+}
+
+func TestHedgeIdleAfterLastAttempt(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	hc := mutableware.NewHandlerContainer[string, int32]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int32]) (int32, error) {
+			call := calls.Add(1)
+			<-release
+			return call, nil
+		})
+	clock := mutablewaretest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	hc.Add(hedge.New[string, int32](time.Second, hedge.OptionClock(clock)))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := hc.Handle(context.Background(), "req")
+		done <- err
+	}()
+	require.True(t, clock.WaitForTimers(1, time.Second))
+	clock.Advance(time.Second)
+
+	// every attempt has been launched, so the handler just waits for them.
+	require.Eventually(t, func() bool {
+		return calls.Load() == 2 && handleWaiting()
+	}, time.Second, time.Millisecond)
+	require.Equal(t, 0, clock.Timers())
+	clock.Advance(time.Minute)
+	require.Eventually(t, handleWaiting, time.Second, time.Millisecond)
+	for i := 0; i < 10; i++ {
+		require.True(t, handleWaiting())
+	}
+
+	close(release)
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,103 @@
+// Package httpware adapts mutableware to net/http.
+package httpware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// Response is a buffered HTTP response.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// recorder is an http.ResponseWriter that buffers what's written to it.
+type recorder struct {
+	header      http.Header
+	body        bytes.Buffer
+	statusCode  int
+	wroteHeader bool
+}
+
+func newRecorder() *recorder {
+	return &recorder{header: http.Header{}}
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(b)
+}
+
+func (r *recorder) WriteHeader(statusCode int) {
+	if r.wroteHeader {
+		return
+	}
+	r.wroteHeader = true
+	r.statusCode = statusCode
+}
+
+func (r *recorder) response() *Response {
+	if !r.wroteHeader {
+		return nil
+	}
+	return &Response{
+		StatusCode: r.statusCode,
+		Header:     r.header,
+		Body:       r.body.Bytes(),
+	}
+}
+
+// writeTo copies a response to w.
+func (resp *Response) writeTo(w http.ResponseWriter) {
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	statusCode := resp.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(resp.Body)
+}
+
+type middlewareHandler struct {
+	middleware func(http.Handler) http.Handler
+}
+
+// FromMiddleware adapts standard net/http middleware into a Handler.
+// The request is served by the middleware, and the handler it wraps calls
+// next with the request it receives. The response written by the middleware
+// is buffered and returned, so middleware that short-circuits the request
+// (like an authentication check) or rewrites the response works as usual.
+//
+// If next fails, the error is returned along with whatever the middleware
+// wrote, which is nil if it wrote nothing.
+func FromMiddleware(middleware func(http.Handler) http.Handler) mutableware.Handler[*http.Request, *Response] {
+	return &middlewareHandler{
+		middleware: middleware,
+	}
+}
+
+func (m *middlewareHandler) Handle(ctx context.Context, request *http.Request, next mutableware.CurriedHandlerFunc[*http.Request, *Response]) (*Response, error) {
+	var err error
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp *Response
+		resp, err = next(r.Context(), r)
+		if err == nil && resp != nil {
+			resp.writeTo(w)
+		}
+	})
+
+	rec := newRecorder()
+	m.middleware(inner).ServeHTTP(rec, request.WithContext(ctx))
+	return rec.response(), err
+}
@@ -0,0 +1,69 @@
+package httpware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/httpware"
+	"github.com/stretchr/testify/require"
+)
+
+func requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("X-Authorized", "yes")
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newContainer(terminalErr error) *mutableware.HandlerContainer[*http.Request, *httpware.Response] {
+	hc := mutableware.NewHandlerContainer[*http.Request, *httpware.Response]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request *http.Request, next mutableware.CurriedHandlerFunc[*http.Request, *httpware.Response]) (*httpware.Response, error) {
+			if terminalErr != nil {
+				return nil, terminalErr
+			}
+			return &httpware.Response{
+				StatusCode: http.StatusCreated,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       []byte("hello " + request.URL.Path),
+			}, nil
+		})
+	hc.Add(httpware.FromMiddleware(requireToken))
+	return hc
+}
+
+func TestFromMiddleware(t *testing.T) {
+	hc := newContainer(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/world", nil)
+	resp, err := hc.Handle(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req.Header.Set("Authorization", "token")
+	resp, err = hc.Handle(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, "hello /world", string(resp.Body))
+	require.Equal(t, "yes", resp.Header.Get("X-Authorized"))
+	require.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+}
+
+func TestFromMiddlewareError(t *testing.T) {
+	expectedErr := errors.New("an_error")
+	hc := newContainer(expectedErr)
+
+	req := httptest.NewRequest(http.MethodGet, "/world", nil)
+	req.Header.Set("Authorization", "token")
+	resp, err := hc.Handle(context.Background(), req)
+	require.ErrorIs(t, err, expectedErr)
+	require.Nil(t, resp)
+}
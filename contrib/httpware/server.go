@@ -0,0 +1,63 @@
+package httpware
+
+import (
+	"net/http"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// WriteFunc writes the result of handling an HTTP request to w.
+type WriteFunc[Response any] func(w http.ResponseWriter, r *http.Request, response Response, err error)
+
+// WriteResponse is a WriteFunc for *Response.
+// Errors are reported with a 500 status. A nil response is written as
+// a 204 status.
+func WriteResponse(w http.ResponseWriter, r *http.Request, response *Response, err error) {
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if response == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	response.writeTo(w)
+}
+
+var defaultWrite = WriteFunc[*Response](WriteResponse)
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+type server[Response any] struct {
+	hc    *mutableware.HandlerContainer[*http.Request, Response]
+	write WriteFunc[Response]
+}
+
+// ToHTTPHandler serves requests with hc, and writes the results with write.
+// If write is nil, WriteResponse is used when Response is *Response.
+// Otherwise, errors are reported with a 500 status and responses are
+// dropped.
+func ToHTTPHandler[Response any](hc *mutableware.HandlerContainer[*http.Request, Response], write WriteFunc[Response]) http.Handler {
+	if write == nil {
+		var ok bool
+		write, ok = any(defaultWrite).(WriteFunc[Response])
+		if !ok {
+			write = func(w http.ResponseWriter, r *http.Request, response Response, err error) {
+				if err != nil {
+					writeError(w, r, err)
+				}
+			}
+		}
+	}
+	return &server[Response]{
+		hc:    hc,
+		write: write,
+	}
+}
+
+func (s *server[Response]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	response, err := s.hc.Handle(r.Context(), r)
+	s.write(w, r, response, err)
+}
@@ -0,0 +1,69 @@
+package httpware_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/httpware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToHTTPHandler(t *testing.T) {
+	hc := newContainer(nil)
+	server := httptest.NewServer(httpware.ToHTTPHandler(hc, nil))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/world")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/world", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "token")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, "yes", resp.Header.Get("X-Authorized"))
+}
+
+func TestToHTTPHandlerWriter(t *testing.T) {
+	type greeting struct {
+		Message string
+	}
+	hc := mutableware.NewHandlerContainer[*http.Request, greeting]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request *http.Request, next mutableware.CurriedHandlerFunc[*http.Request, greeting]) (greeting, error) {
+			if request.URL.Path == "/fail" {
+				return greeting{}, errors.New("an_error")
+			}
+			return greeting{Message: "hello"}, nil
+		})
+	handler := httpware.ToHTTPHandler(hc, func(w http.ResponseWriter, r *http.Request, response greeting, err error) {
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"Message":"hello"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+
+	// the default writer reports errors
+	rec = httptest.NewRecorder()
+	httpware.ToHTTPHandler(hc, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,116 @@
+// Package jobqueue adapts a HandlerContainer to job queue libraries.
+//
+// Task types are generic, so any library can be used. For example, with
+// asynq the task type is *asynq.Task, and the functions returned here can be
+// converted to asynq.HandlerFunc.
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// ErrDecode is returned when a task can't be decoded into a request.
+// It's never retryable.
+var ErrDecode = errors.New("decodeTask")
+
+// TaskFunc processes a task.
+type TaskFunc[Task any] func(ctx context.Context, task Task) error
+
+type builtOptions struct {
+	complete  any
+	permanent func(error) error
+}
+
+// Option is an option for the NewTaskHandler(...) and NewMiddleware(...)
+// functions.
+type Option func(*builtOptions)
+
+// OptionComplete is called with the response when a task succeeds, to
+// record its result. If it fails, so does the task.
+// The types must match those of the handler, or NewTaskHandler panics.
+func OptionComplete[Task any, Response any](complete func(ctx context.Context, task Task, response Response) error) Option {
+	return func(o *builtOptions) {
+		o.complete = complete
+	}
+}
+
+// OptionPermanentError wraps task errors that are permanent with wrap, so
+// the job queue can stop retrying them. Errors are classified by the
+// container's IsRetryable, except that errors caused by a context ending,
+// like a worker shutting down or a task timing out, are always retried
+// unless they're marked permanent.
+// For asynq, wrap could join the error with asynq.SkipRetry.
+func OptionPermanentError(wrap func(error) error) Option {
+	return func(o *builtOptions) {
+		o.permanent = wrap
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+// taskError applies OptionPermanentError, classifying err with isRetryable.
+func (o *builtOptions) taskError(ctx context.Context, isRetryable func(context.Context, error) bool, err error) error {
+	if err == nil || o.permanent == nil {
+		return err
+	}
+	var retryable mutableware.Retryable
+	switch {
+	case errors.As(err, &retryable):
+		if retryable.Retryable() {
+			return err
+		}
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return err
+	case isRetryable(ctx, err):
+		return err
+	}
+	return o.permanent(err)
+}
+
+// NewTaskHandler creates a task handler that decodes each task's payload
+// into a request and sends it through hc. The task succeeds if hc handles
+// the request without an error.
+func NewTaskHandler[Task any, Request any, Response any](hc *mutableware.HandlerContainer[Request, Response], decode func(Task) (Request, error), options ...Option) TaskFunc[Task] {
+	opts := buildOptions(options)
+	complete, ok := opts.complete.(func(context.Context, Task, Response) error)
+	if opts.complete != nil && !ok {
+		panic(fmt.Sprintf("jobqueue: OptionComplete needs a %T, not a %T", complete, opts.complete))
+	}
+
+	return func(ctx context.Context, task Task) error {
+		request, err := decode(task)
+		if err != nil {
+			return opts.taskError(ctx, hc.IsRetryable, mutableware.MarkPermanent(fmt.Errorf("%w: %w", ErrDecode, err)))
+		}
+		response, err := hc.Handle(ctx, request)
+		if err == nil && complete != nil {
+			err = complete(ctx, task, response)
+		}
+		return opts.taskError(ctx, hc.IsRetryable, err)
+	}
+}
+
+// NewMiddleware creates task middleware that sends each task through hc.
+// When a task falls through the chain, the task handler being wrapped runs,
+// so handlers in hc can act before and after it.
+func NewMiddleware[Task any](hc *mutableware.HandlerContainer[Task, struct{}], options ...Option) func(next TaskFunc[Task]) TaskFunc[Task] {
+	opts := buildOptions(options)
+	return func(next TaskFunc[Task]) TaskFunc[Task] {
+		return func(ctx context.Context, task Task) error {
+			_, err := hc.HandleNext(ctx, task, func(ctx context.Context, task Task) (struct{}, error) {
+				return struct{}{}, next(ctx, task)
+			})
+			return opts.taskError(ctx, hc.IsRetryable, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package jobqueue_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/jobqueue"
+	"github.com/stretchr/testify/require"
+)
+
+type task struct {
+	Payload []byte
+	Result  string
+}
+
+type email struct {
+	To string
+}
+
+var errSkipRetry = errors.New("skipRetry")
+
+func decode(t *task) (email, error) {
+	var e email
+	err := json.Unmarshal(t.Payload, &e)
+	return e, err
+}
+
+func TestNewTaskHandler(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[email, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request email, next mutableware.CurriedHandlerFunc[email, string]) (string, error) {
+			if request.To == "" {
+				return "", mutableware.MarkPermanent(errors.New("no recipient"))
+			}
+			return "sent to " + request.To, nil
+		})
+	handle := jobqueue.NewTaskHandler(hc, decode,
+		jobqueue.OptionComplete(func(ctx context.Context, t *task, response string) error {
+			t.Result = response
+			return nil
+		}),
+		jobqueue.OptionPermanentError(func(err error) error {
+			return errors.Join(err, errSkipRetry)
+		}))
+
+	ok := &task{Payload: []byte(`{"To":"a@example.com"}`)}
+	require.NoError(t, handle(context.Background(), ok))
+	require.Equal(t, "sent to a@example.com", ok.Result)
+
+	err := handle(context.Background(), &task{Payload: []byte(`{}`)})
+	require.ErrorIs(t, err, errSkipRetry)
+
+	err = handle(context.Background(), &task{Payload: []byte(`nope`)})
+	require.ErrorIs(t, err, jobqueue.ErrDecode)
+	require.ErrorIs(t, err, errSkipRetry)
+}
+
+func TestCompleteTypeMismatch(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[email, string]()
+	require.Panics(t, func() {
+		jobqueue.NewTaskHandler(hc, decode,
+			jobqueue.OptionComplete(func(ctx context.Context, t *task, response int) error {
+				return nil
+			}))
+	})
+}
+
+func TestNewMiddleware(t *testing.T) {
+	steps := []string{}
+	hc := mutableware.NewHandlerContainer[*task, struct{}]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request *task, next mutableware.CurriedHandlerFunc[*task, struct{}]) (struct{}, error) {
+			steps = append(steps, "before")
+			resp, err := next(ctx, request)
+			steps = append(steps, "after")
+			return resp, err
+		})
+
+	middleware := jobqueue.NewMiddleware(hc)
+	handle := middleware(func(ctx context.Context, t *task) error {
+		steps = append(steps, "task")
+		return nil
+	})
+	require.NoError(t, handle(context.Background(), &task{}))
+	require.Equal(t, []string{"before", "task", "after"}, steps)
+}
+
+func TestPermanentErrorClassification(t *testing.T) {
+	errInvalid := errors.New("invalid")
+	hc := mutableware.NewHandlerContainer[email, string](
+		mutableware.ContainerOptionRetryClassifier(func(err error) bool {
+			return !errors.Is(err, errInvalid)
+		}))
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request email, next mutableware.CurriedHandlerFunc[email, string]) (string, error) {
+			switch request.To {
+			case "invalid":
+				return "", errInvalid
+			case "slow":
+				<-ctx.Done()
+				return "", ctx.Err()
+			}
+			return "", errors.New("flaky")
+		})
+	handle := jobqueue.NewTaskHandler(hc, decode,
+		jobqueue.OptionPermanentError(func(err error) error {
+			return errors.Join(err, errSkipRetry)
+		}))
+
+	// the container's classifier decides.
+	err := handle(context.Background(), &task{Payload: []byte(`{"To":"invalid"}`)})
+	require.ErrorIs(t, err, errSkipRetry)
+	err = handle(context.Background(), &task{Payload: []byte(`{"To":"flaky"}`)})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, errSkipRetry)
+
+	// tasks interrupted by their context are retried.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = handle(ctx, &task{Payload: []byte(`{"To":"slow"}`)})
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotErrorIs(t, err, errSkipRetry)
+}
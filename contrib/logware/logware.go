@@ -0,0 +1,124 @@
+// Package logware provides a handler that logs requests with log/slog.
+package logware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+type builtOptions struct {
+	startLevel     slog.Level
+	finishLevel    slog.Level
+	errorLevel     slog.Level
+	formatRequest  any
+	formatResponse any
+}
+
+// Option is an option for the New(...) function.
+type Option func(*builtOptions)
+
+// OptionLevels sets the levels used when a request starts, when it finishes
+// successfully, and when it fails. The defaults are Debug, Info, and Error.
+func OptionLevels(start slog.Level, finish slog.Level, failure slog.Level) Option {
+	return func(o *builtOptions) {
+		o.startLevel = start
+		o.finishLevel = finish
+		o.errorLevel = failure
+	}
+}
+
+// OptionRequestFormatter logs requests with the value returned by format.
+// Requests aren't logged without a formatter.
+// The Request type must match the handler's, or New panics.
+func OptionRequestFormatter[Request any](format func(Request) slog.Value) Option {
+	return func(o *builtOptions) {
+		o.formatRequest = format
+	}
+}
+
+// OptionResponseFormatter logs responses with the value returned by format.
+// Responses aren't logged without a formatter.
+// The Response type must match the handler's, or New panics.
+func OptionResponseFormatter[Response any](format func(Response) slog.Value) Option {
+	return func(o *builtOptions) {
+		o.formatResponse = format
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{
+		startLevel:  slog.LevelDebug,
+		finishLevel: slog.LevelInfo,
+		errorLevel:  slog.LevelError,
+	}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+type logHandler[Request any, Response any] struct {
+	logger         *slog.Logger
+	opts           *builtOptions
+	formatRequest  func(Request) slog.Value
+	formatResponse func(Response) slog.Value
+}
+
+// New creates a Handler that logs when each request starts and finishes,
+// along with how long the rest of the chain took, the handler path from the
+// context, and any error.
+func New[Request any, Response any](logger *slog.Logger, options ...Option) mutableware.Handler[Request, Response] {
+	opts := buildOptions(options)
+	formatRequest, ok := opts.formatRequest.(func(Request) slog.Value)
+	if opts.formatRequest != nil && !ok {
+		panic(fmt.Sprintf("logware: OptionRequestFormatter needs a %T, not a %T", formatRequest, opts.formatRequest))
+	}
+	formatResponse, ok := opts.formatResponse.(func(Response) slog.Value)
+	if opts.formatResponse != nil && !ok {
+		panic(fmt.Sprintf("logware: OptionResponseFormatter needs a %T, not a %T", formatResponse, opts.formatResponse))
+	}
+	return &logHandler[Request, Response]{
+		logger:         logger,
+		opts:           opts,
+		formatRequest:  formatRequest,
+		formatResponse: formatResponse,
+	}
+}
+
+func (l *logHandler[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	attrs := []slog.Attr{
+		slog.String("path", formatPath(mutableware.GetHandlerInfoFromContext(ctx))),
+	}
+	if l.formatRequest != nil {
+		attrs = append(attrs, slog.Attr{Key: "request", Value: l.formatRequest(request)})
+	}
+	l.logger.LogAttrs(ctx, l.opts.startLevel, "request started", attrs...)
+
+	start := time.Now()
+	response, err := next(ctx, request)
+	attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+		l.logger.LogAttrs(ctx, l.opts.errorLevel, "request failed", attrs...)
+		return response, err
+	}
+	if l.formatResponse != nil {
+		attrs = append(attrs, slog.Attr{Key: "response", Value: l.formatResponse(response)})
+	}
+	l.logger.LogAttrs(ctx, l.opts.finishLevel, "request finished", attrs...)
+	return response, err
+}
+
+func formatPath(path []mutableware.HandlerInfo) string {
+	parts := make([]string, 0, len(path))
+	for _, info := range path {
+		parts = append(parts, info.String())
+	}
+	return strings.Join(parts, ">")
+}
@@ -0,0 +1,72 @@
+package logware_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/logware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogware(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	hc := mutableware.NewHandlerContainer[int, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, string]) (string, error) {
+			if request < 0 {
+				return "", fmt.Errorf("negative")
+			}
+			return fmt.Sprint(request), nil
+		})
+	hc.Add(logware.New[int, string](logger,
+		logware.OptionRequestFormatter(func(r int) slog.Value { return slog.IntValue(r) }),
+		logware.OptionResponseFormatter(func(r string) slog.Value { return slog.StringValue(r) }),
+	), mutableware.AddOptionName("log"))
+
+	_, err := hc.Handle(context.Background(), 3)
+	require.NoError(t, err)
+	_, err = hc.Handle(context.Background(), -1)
+	require.Error(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+
+	records := []map[string]any{}
+	for _, line := range lines {
+		record := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+
+	require.Equal(t, "request started", records[0]["msg"])
+	require.Equal(t, "DEBUG", records[0]["level"])
+	require.Equal(t, "11(log)", records[0]["path"])
+	require.Equal(t, float64(3), records[0]["request"])
+
+	require.Equal(t, "request finished", records[1]["msg"])
+	require.Equal(t, "INFO", records[1]["level"])
+	require.Equal(t, "3", records[1]["response"])
+	require.Contains(t, records[1], "duration")
+
+	require.Equal(t, "request failed", records[3]["msg"])
+	require.Equal(t, "ERROR", records[3]["level"])
+	require.Contains(t, records[3]["error"], "negative")
+}
+
+func TestFormatterTypeMismatch(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil))
+	require.Panics(t, func() {
+		logware.New[string, string](logger, logware.OptionRequestFormatter(slog.IntValue))
+	})
+	require.Panics(t, func() {
+		logware.New[string, string](logger, logware.OptionResponseFormatter(slog.IntValue))
+	})
+}
@@ -0,0 +1,91 @@
+// Package otelware provides handlers that record OpenTelemetry traces for
+// a HandlerContainer.
+package otelware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/erinpentecost/mutableware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	attrHandlerID   = attribute.Key("mutableware.handler.id")
+	attrHandlerName = attribute.Key("mutableware.handler.name")
+	attrHandlerPath = attribute.Key("mutableware.handler.path")
+)
+
+type chainHandler[Request any, Response any] struct {
+	tracer   trace.Tracer
+	spanName string
+}
+
+// New creates a Handler that opens a span named spanName around the rest of
+// the chain. Add it last so it's invoked first, and every span created by
+// the rest of the chain is its child.
+func New[Request any, Response any](tracer trace.Tracer, spanName string) mutableware.Handler[Request, Response] {
+	return &chainHandler[Request, Response]{
+		tracer:   tracer,
+		spanName: spanName,
+	}
+}
+
+func (c *chainHandler[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	ctx, span := c.tracer.Start(ctx, c.spanName)
+	defer span.End()
+
+	response, err := next(ctx, request)
+	recordError(span, err)
+	return response, err
+}
+
+type wrappedHandler[Request any, Response any] struct {
+	mutableware.Handler[Request, Response]
+	tracer trace.Tracer
+}
+
+// Wrap opens a child span around a single handler, including its calls to
+// next. The span is named after the handler, and records its HandlerInfo and
+// the handler path from the context as attributes.
+func Wrap[Request any, Response any](tracer trace.Tracer, handler mutableware.Handler[Request, Response]) mutableware.Handler[Request, Response] {
+	return &wrappedHandler[Request, Response]{
+		Handler: handler,
+		tracer:  tracer,
+	}
+}
+
+func (w *wrappedHandler[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	stack := mutableware.GetHandlerInfoFromContext(ctx)
+	name := "handler"
+	attrs := []attribute.KeyValue{}
+	if len(stack) > 0 {
+		info := stack[len(stack)-1]
+		name = info.String()
+		path := make([]string, 0, len(stack))
+		for _, pathInfo := range stack {
+			path = append(path, pathInfo.String())
+		}
+		attrs = append(attrs,
+			attrHandlerID.Int64(int64(info.ID)),
+			attrHandlerName.String(info.Name),
+			attrHandlerPath.String(strings.Join(path, ">")),
+		)
+	}
+
+	ctx, span := w.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	defer span.End()
+
+	response, err := w.Handler.Handle(ctx, request, next)
+	recordError(span, err)
+	return response, err
+}
+
+func recordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
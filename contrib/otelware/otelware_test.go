@@ -0,0 +1,49 @@
+package otelware_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/otelware"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	hc := mutableware.NewHandlerContainer[int, string]()
+	hc.Add(otelware.Wrap(tracer, mutableware.HandlerFunc[int, string](
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, string]) (string, error) {
+			return "", fmt.Errorf("an_error")
+		}).Handler()), mutableware.AddOptionName("failer"))
+	hc.Add(otelware.New[int, string](tracer, "chain"))
+
+	_, err := hc.Handle(context.Background(), 1)
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	child, parent := spans[0], spans[1]
+
+	require.Equal(t, "chain", parent.Name())
+	require.Equal(t, codes.Error, parent.Status().Code)
+
+	require.Equal(t, "10(failer)", child.Name())
+	require.Equal(t, parent.SpanContext().SpanID(), child.Parent().SpanID())
+	require.Equal(t, codes.Error, child.Status().Code)
+	attrs := map[string]string{}
+	for _, attr := range child.Attributes() {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	require.Equal(t, map[string]string{
+		"mutableware.handler.id":   "10",
+		"mutableware.handler.name": "failer",
+		"mutableware.handler.path": "11>10(failer)",
+	}, attrs)
+}
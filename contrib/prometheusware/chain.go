@@ -0,0 +1,54 @@
+package prometheusware
+
+import (
+	"github.com/erinpentecost/mutableware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	chainHandlersDesc = prometheus.NewDesc(
+		"mutableware_chain_handlers",
+		"Number of handlers in the container.",
+		[]string{"container"}, nil)
+	chainMutationsDesc = prometheus.NewDesc(
+		"mutableware_chain_mutations_total",
+		"Number of times the container has been changed.",
+		[]string{"container"}, nil)
+	chainLastMutationDesc = prometheus.NewDesc(
+		"mutableware_chain_last_mutation_timestamp_seconds",
+		"When the container was last changed.",
+		[]string{"container"}, nil)
+)
+
+// chainCollector reports the ChainStats of a container.
+type chainCollector struct {
+	container string
+	stats     func() mutableware.ChainStats
+}
+
+// NewChainCollector creates a Collector that reports the size of the
+// container named container, and how often it changes. Pass the container's
+// ChainStats method as stats.
+func NewChainCollector(container string, stats func() mutableware.ChainStats) prometheus.Collector {
+	return &chainCollector{
+		container: container,
+		stats:     stats,
+	}
+}
+
+func (c *chainCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- chainHandlersDesc
+	ch <- chainMutationsDesc
+	ch <- chainLastMutationDesc
+}
+
+func (c *chainCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(chainHandlersDesc, prometheus.GaugeValue, float64(stats.Handlers), c.container)
+	ch <- prometheus.MustNewConstMetric(chainMutationsDesc, prometheus.CounterValue, float64(stats.Mutations), c.container)
+	lastMutation := float64(0)
+	if !stats.LastMutation.IsZero() {
+		lastMutation = float64(stats.LastMutation.UnixNano()) / 1e9
+	}
+	ch <- prometheus.MustNewConstMetric(chainLastMutationDesc, prometheus.GaugeValue, lastMutation, c.container)
+}
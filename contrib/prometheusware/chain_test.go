@@ -0,0 +1,36 @@
+package prometheusware_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/prometheusware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainCollector(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, string]()
+	hc.Remove(hc.AddAnonymousHandler(nil))
+	hc.AddAnonymousHandler(nil)
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(prometheusware.NewChainCollector("animals", hc.ChainStats))
+
+	expected := `
+# HELP mutableware_chain_handlers Number of handlers in the container.
+# TYPE mutableware_chain_handlers gauge
+mutableware_chain_handlers{container="animals"} 1
+# HELP mutableware_chain_mutations_total Number of times the container has been changed.
+# TYPE mutableware_chain_mutations_total counter
+mutableware_chain_mutations_total{container="animals"} 3
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"mutableware_chain_handlers", "mutableware_chain_mutations_total"))
+
+	problems, err := testutil.GatherAndLint(reg)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+}
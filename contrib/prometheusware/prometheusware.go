@@ -0,0 +1,138 @@
+// Package prometheusware provides handlers that record Prometheus metrics
+// for a HandlerContainer.
+package prometheusware
+
+import (
+	"context"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds the collectors for a single container.
+//
+// The collectors are labeled with the container name and the handler name.
+// Measurements of the whole chain have an empty handler label.
+type Metrics struct {
+	container string
+	requests  *prometheus.CounterVec
+	errors    *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+}
+
+// New creates the collectors for the container named container and
+// registers them with reg. Metrics for several containers can share a
+// Registerer; the collectors are only registered once.
+func New(reg prometheus.Registerer, container string) (*Metrics, error) {
+	labels := []string{"container", "handler"}
+	m := &Metrics{
+		container: container,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "mutableware",
+			Name:      "requests_total",
+			Help:      "Number of requests handled.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "mutableware",
+			Name:      "errors_total",
+			Help:      "Number of requests that returned an error.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "mutableware",
+			Name:      "duration_seconds",
+			Help:      "Time spent handling requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+
+	var err error
+	if m.requests, err = register(reg, m.requests); err != nil {
+		return nil, err
+	}
+	if m.errors, err = register(reg, m.errors); err != nil {
+		return nil, err
+	}
+	if m.duration, err = register(reg, m.duration); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// register registers c, or returns the equivalent collector that is
+// already registered.
+func register[C prometheus.Collector](reg prometheus.Registerer, c C) (C, error) {
+	if err := reg.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			if existing, ok := are.ExistingCollector.(C); ok {
+				return existing, nil
+			}
+		}
+		return c, err
+	}
+	return c, nil
+}
+
+func (m *Metrics) observe(handler string, start time.Time, err error) {
+	m.requests.WithLabelValues(m.container, handler).Inc()
+	if err != nil {
+		m.errors.WithLabelValues(m.container, handler).Inc()
+	}
+	m.duration.WithLabelValues(m.container, handler).Observe(time.Since(start).Seconds())
+}
+
+type containerHandler[Request any, Response any] struct {
+	metrics *Metrics
+}
+
+// Handler creates a Handler that measures every request sent through the
+// rest of the chain. Add it last so it's invoked first.
+func Handler[Request any, Response any](m *Metrics) mutableware.Handler[Request, Response] {
+	return &containerHandler[Request, Response]{metrics: m}
+}
+
+func (c *containerHandler[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	start := time.Now()
+	response, err := next(ctx, request)
+	c.metrics.observe("", start, err)
+	return response, err
+}
+
+// UnnamedHandler is the handler label of measurements from a wrapped handler
+// that was added without a name.
+const UnnamedHandler = "unnamed"
+
+type wrappedHandler[Request any, Response any] struct {
+	mutableware.Handler[Request, Response]
+	metrics *Metrics
+}
+
+// Wrap measures a single handler, including its calls to next.
+// It is labeled with the name given to the handler with
+// mutableware.AddOptionName. Handlers without a name share the
+// UnnamedHandler label; HandlerIDs aren't used, since every Add mints a new
+// one and the label set would grow without bound.
+func Wrap[Request any, Response any](m *Metrics, handler mutableware.Handler[Request, Response]) mutableware.Handler[Request, Response] {
+	return &wrappedHandler[Request, Response]{
+		Handler: handler,
+		metrics: m,
+	}
+}
+
+func (w *wrappedHandler[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	start := time.Now()
+	response, err := w.Handler.Handle(ctx, request, next)
+	w.metrics.observe(handlerLabel(ctx), start, err)
+	return response, err
+}
+
+func handlerLabel(ctx context.Context) string {
+	info, ok := mutableware.GetCurrentHandlerInfoFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if info.Name != "" {
+		return info.Name
+	}
+	return UnnamedHandler
+}
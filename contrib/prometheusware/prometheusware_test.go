@@ -0,0 +1,74 @@
+package prometheusware_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/prometheusware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics, err := prometheusware.New(reg, "animals")
+	require.NoError(t, err)
+
+	hc := mutableware.NewHandlerContainer[int, string]()
+	hc.Add(prometheusware.Wrap(metrics, mutableware.HandlerFunc[int, string](
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, string]) (string, error) {
+			if request < 0 {
+				return "", fmt.Errorf("negative")
+			}
+			return fmt.Sprint(request), nil
+		}).Handler()), mutableware.AddOptionName("printer"))
+	for i := 0; i < 2; i++ {
+		hc.Add(prometheusware.Wrap(metrics, mutableware.HandlerFunc[int, string](nil).Handler()))
+	}
+	hc.Add(prometheusware.Handler[int, string](metrics))
+
+	for _, req := range []int{1, 2, -1} {
+		_, _ = hc.Handle(context.Background(), req)
+	}
+
+	count, err := testutil.GatherAndCount(reg, "mutableware_requests_total")
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	problems, err := testutil.GatherAndLint(reg)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	totals := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			handler := ""
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "handler" {
+					handler = label.GetValue()
+				}
+			}
+			switch family.GetName() {
+			case "mutableware_requests_total", "mutableware_errors_total":
+				totals[family.GetName()+"/"+handler] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+	require.Equal(t, map[string]float64{
+		"mutableware_requests_total/":                                 3,
+		"mutableware_requests_total/printer":                          3,
+		"mutableware_errors_total/":                                   1,
+		"mutableware_errors_total/printer":                            1,
+		"mutableware_requests_total/" + prometheusware.UnnamedHandler: 6,
+		"mutableware_errors_total/" + prometheusware.UnnamedHandler:   2,
+	}, totals)
+
+	// a second container can share the registry
+	_, err = prometheusware.New(reg, "plants")
+	require.NoError(t, err)
+}
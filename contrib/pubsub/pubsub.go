@@ -0,0 +1,118 @@
+// Package pubsub mounts a HandlerContainer as a message handler for pub/sub
+// frameworks.
+//
+// Message types are generic, so any framework can be used. Messages that
+// have a Context() context.Context method, like Watermill's
+// *message.Message, are handled with their context.
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// ErrDecode is returned when a message can't be decoded into a request.
+var ErrDecode = errors.New("decodeMessage")
+
+// Acker is implemented by messages that can be acknowledged.
+type Acker interface {
+	Ack() bool
+	Nack() bool
+}
+
+type contexter interface {
+	Context() context.Context
+}
+
+type builtOptions struct {
+	encode      any
+	acknowledge bool
+}
+
+// Option is an option for the NewHandler(...) and NewNoPublishHandler(...)
+// functions.
+type Option func(*builtOptions)
+
+// OptionEncode turns each response into messages to publish.
+// Without it, no messages are published.
+// The types must match those of the handler, or NewHandler panics.
+func OptionEncode[Response any, Message any](encode func(Response) ([]Message, error)) Option {
+	return func(o *builtOptions) {
+		o.encode = encode
+	}
+}
+
+// OptionAcknowledge acks messages that implement Acker when they're handled
+// successfully, and nacks them when they fail. Leave this off for
+// frameworks like Watermill that ack based on the returned error.
+func OptionAcknowledge() Option {
+	return func(o *builtOptions) {
+		o.acknowledge = true
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+// NewHandler creates a message handler that decodes each message into a
+// request and sends it through hc. The messages returned are the ones made
+// from the response by OptionEncode.
+func NewHandler[Message any, Request any, Response any](hc *mutableware.HandlerContainer[Request, Response], decode func(Message) (Request, error), options ...Option) func(Message) ([]Message, error) {
+	opts := buildOptions(options)
+	encode, ok := opts.encode.(func(Response) ([]Message, error))
+	if opts.encode != nil && !ok {
+		panic(fmt.Sprintf("pubsub: OptionEncode needs a %T, not a %T", encode, opts.encode))
+	}
+
+	return func(msg Message) (out []Message, err error) {
+		if opts.acknowledge {
+			defer func() {
+				settle(msg, err)
+			}()
+		}
+
+		request, err := decode(msg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDecode, err)
+		}
+		ctx := context.Background()
+		if c, ok := any(msg).(contexter); ok {
+			ctx = c.Context()
+		}
+		response, err := hc.Handle(ctx, request)
+		if err != nil || encode == nil {
+			return nil, err
+		}
+		return encode(response)
+	}
+}
+
+// NewNoPublishHandler is like NewHandler, for frameworks whose handlers
+// don't publish messages.
+func NewNoPublishHandler[Message any, Request any, Response any](hc *mutableware.HandlerContainer[Request, Response], decode func(Message) (Request, error), options ...Option) func(Message) error {
+	handle := NewHandler(hc, decode, options...)
+	return func(msg Message) error {
+		_, err := handle(msg)
+		return err
+	}
+}
+
+func settle(msg any, err error) {
+	acker, ok := msg.(Acker)
+	if !ok {
+		return
+	}
+	if err != nil {
+		acker.Nack()
+		return
+	}
+	acker.Ack()
+}
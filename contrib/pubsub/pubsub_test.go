@@ -0,0 +1,100 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/pubsub"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+type message struct {
+	ctx     context.Context
+	payload string
+	acked   bool
+	nacked  bool
+}
+
+func (m *message) Context() context.Context {
+	return m.ctx
+}
+
+func (m *message) Ack() bool {
+	m.acked = true
+	return true
+}
+
+func (m *message) Nack() bool {
+	m.nacked = true
+	return true
+}
+
+func newMessage(payload string) *message {
+	return &message{
+		ctx:     context.WithValue(context.Background(), ctxKey{}, "from message"),
+		payload: payload,
+	}
+}
+
+func decode(msg *message) (int, error) {
+	return strconv.Atoi(msg.payload)
+}
+
+func newContainer(t *testing.T) *mutableware.HandlerContainer[int, int] {
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			require.Equal(t, "from message", ctx.Value(ctxKey{}))
+			if request < 0 {
+				return 0, errors.New("negative")
+			}
+			return request * 2, nil
+		})
+	return hc
+}
+
+func TestNewHandler(t *testing.T) {
+	handle := pubsub.NewHandler(newContainer(t), decode,
+		pubsub.OptionEncode(func(response int) ([]*message, error) {
+			return []*message{newMessage(strconv.Itoa(response))}, nil
+		}))
+
+	out, err := handle(newMessage("21"))
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, "42", out[0].payload)
+
+	_, err = handle(newMessage("-1"))
+	require.Error(t, err)
+
+	_, err = handle(newMessage("nope"))
+	require.ErrorIs(t, err, pubsub.ErrDecode)
+}
+
+func TestEncodeTypeMismatch(t *testing.T) {
+	require.Panics(t, func() {
+		pubsub.NewHandler(newContainer(t), decode,
+			pubsub.OptionEncode(func(response string) ([]*message, error) {
+				return nil, nil
+			}))
+	})
+}
+
+func TestAcknowledge(t *testing.T) {
+	handle := pubsub.NewNoPublishHandler(newContainer(t), decode, pubsub.OptionAcknowledge())
+
+	ok := newMessage("1")
+	require.NoError(t, handle(ok))
+	require.True(t, ok.acked)
+	require.False(t, ok.nacked)
+
+	bad := newMessage("-1")
+	require.Error(t, handle(bad))
+	require.False(t, bad.acked)
+	require.True(t, bad.nacked)
+}
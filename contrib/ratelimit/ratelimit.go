@@ -0,0 +1,178 @@
+// Package ratelimit provides a token bucket rate limiting handler.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// ErrLimited is returned when a request is rejected by the rate limiter.
+var ErrLimited = errors.New("rateLimited")
+
+// sweepInterval is how often idle buckets are forgotten.
+const sweepInterval = time.Minute
+
+type builtOptions struct {
+	wait  bool
+	key   any
+	clock mutableware.Clock
+}
+
+// Option is an option for the New(...) function.
+type Option func(*builtOptions)
+
+// OptionWait makes requests over the limit wait for a token instead of
+// failing with ErrLimited. Waiting requests still fail if their context ends.
+func OptionWait() Option {
+	return func(o *builtOptions) {
+		o.wait = true
+	}
+}
+
+// OptionKey gives each key returned by key its own bucket, so requests are
+// limited per key instead of all together.
+// The Request type must match the handler's, or New panics.
+func OptionKey[Request any](key func(Request) string) Option {
+	return func(o *builtOptions) {
+		o.key = key
+	}
+}
+
+// OptionClock makes buckets refill, and waiting requests wait, according to
+// clock instead of the real time.
+func OptionClock(clock mutableware.Clock) Option {
+	return func(o *builtOptions) {
+		o.clock = clock
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{
+		clock: mutableware.SystemClock(),
+	}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type limiter[Request any, Response any] struct {
+	rate  float64
+	burst float64
+	wait  bool
+	key   func(Request) string
+	clock mutableware.Clock
+
+	mux       sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// New creates a Handler that lets requests through to the rest of the chain
+// at rate requests per second, with bursts of up to burst requests.
+func New[Request any, Response any](rate float64, burst int, options ...Option) mutableware.Handler[Request, Response] {
+	opts := buildOptions(options)
+	key, ok := opts.key.(func(Request) string)
+	if opts.key != nil && !ok {
+		panic(fmt.Sprintf("ratelimit: OptionKey needs a %T, not a %T", key, opts.key))
+	}
+	return &limiter[Request, Response]{
+		rate:      rate,
+		burst:     float64(max(burst, 1)),
+		wait:      opts.wait,
+		key:       key,
+		clock:     opts.clock,
+		buckets:   map[string]*bucket{},
+		lastSweep: opts.clock.Now(),
+	}
+}
+
+func (l *limiter[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	var zero Response
+
+	key := ""
+	if l.key != nil {
+		key = l.key(request)
+	}
+
+	delay, ok := l.take(key)
+	if !ok {
+		return zero, ErrLimited
+	}
+	if delay > 0 {
+		fired := make(chan struct{})
+		stop := l.clock.AfterFunc(delay, func() {
+			close(fired)
+		})
+		select {
+		case <-ctx.Done():
+			stop()
+			l.refund(key)
+			return zero, ctx.Err()
+		case <-fired:
+		}
+	}
+	return next(ctx, request)
+}
+
+// take removes a token from the key's bucket. If the bucket is empty and
+// the limiter waits, the token is borrowed and the returned delay is how
+// long until it's available.
+func (l *limiter[Request, Response]) take(key string) (time.Duration, bool) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := l.clock.Now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	if !l.wait || l.rate <= 0 {
+		return 0, false
+	}
+	b.tokens--
+	return time.Duration(-b.tokens / l.rate * float64(time.Second)), true
+}
+
+func (l *limiter[Request, Response]) refund(key string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if b, ok := l.buckets[key]; ok {
+		b.tokens = min(l.burst, b.tokens+1)
+	}
+}
+
+// sweep forgets buckets that have refilled, since they're
+// indistinguishable from new ones.
+// The lock must be held.
+func (l *limiter[Request, Response]) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
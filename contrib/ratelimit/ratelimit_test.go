@@ -0,0 +1,82 @@
+package ratelimit_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/ratelimit"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func newContainer(limiter mutableware.Handler[string, string]) *mutableware.HandlerContainer[string, string] {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "ok", nil
+		})
+	hc.Add(limiter)
+	return hc
+}
+
+func TestReject(t *testing.T) {
+	hc := newContainer(ratelimit.New[string, string](0.001, 2))
+
+	for i := 0; i < 2; i++ {
+		_, err := hc.Handle(context.Background(), "req")
+		require.NoError(t, err)
+	}
+	_, err := hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, ratelimit.ErrLimited)
+}
+
+func TestPerKey(t *testing.T) {
+	hc := newContainer(ratelimit.New[string, string](0.001, 1,
+		ratelimit.OptionKey(func(r string) string {
+			return strings.Split(r, "/")[0]
+		})))
+
+	_, err := hc.Handle(context.Background(), "a/1")
+	require.NoError(t, err)
+	_, err = hc.Handle(context.Background(), "b/1")
+	require.NoError(t, err)
+	_, err = hc.Handle(context.Background(), "a/2")
+	require.ErrorIs(t, err, ratelimit.ErrLimited)
+}
+
+func TestKeyTypeMismatch(t *testing.T) {
+	require.Panics(t, func() {
+		ratelimit.New[string, string](1, 1, ratelimit.OptionKey(func(r int) string { return "" }))
+	})
+}
+
+func TestWait(t *testing.T) {
+	clock := mutablewaretest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	hc := newContainer(ratelimit.New[string, string](1, 1,
+		ratelimit.OptionWait(), ratelimit.OptionClock(clock)))
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := hc.Handle(context.Background(), "req")
+		done <- err
+	}()
+	require.True(t, clock.WaitForTimers(1, time.Second))
+	select {
+	case <-done:
+		t.Fatal("request didn't wait for a token")
+	default:
+	}
+	clock.Advance(time.Second)
+	require.NoError(t, <-done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = hc.Handle(ctx, "req")
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,73 @@
+// Package requestid provides a handler that gives each request a unique ID.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+type builtOptions struct {
+	generate func() string
+	override bool
+}
+
+// Option is an option for the New(...) function.
+type Option func(*builtOptions)
+
+// OptionGenerator replaces the default random hex ID generator.
+func OptionGenerator(generate func() string) Option {
+	return func(o *builtOptions) {
+		o.generate = generate
+	}
+}
+
+// OptionOverride generates a new ID even when the context already has one.
+func OptionOverride() Option {
+	return func(o *builtOptions) {
+		o.override = true
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{
+		generate: randomID,
+	}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+type requestIDHandler[Request any, Response any] struct {
+	generate func() string
+	override bool
+}
+
+// New creates a Handler that attaches a new request ID to the context passed
+// down the rest of the chain. Handlers can read it with
+// mutableware.GetRequestIDFromContext.
+// If the context already has a request ID, such as when a request is passed
+// between containers, it's kept.
+func New[Request any, Response any](options ...Option) mutableware.Handler[Request, Response] {
+	opts := buildOptions(options)
+	return &requestIDHandler[Request, Response]{
+		generate: opts.generate,
+		override: opts.override,
+	}
+}
+
+func (r *requestIDHandler[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	if _, ok := mutableware.GetRequestIDFromContext(ctx); !ok || r.override {
+		ctx = mutableware.ContextWithRequestID(ctx, r.generate())
+	}
+	return next(ctx, request)
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
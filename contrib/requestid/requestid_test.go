@@ -0,0 +1,69 @@
+package requestid_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/requestid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen []string
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			id, ok := mutableware.GetRequestIDFromContext(ctx)
+			require.True(t, ok)
+			seen = append(seen, id)
+			if request == "fail" {
+				return "", fmt.Errorf("an_error")
+			}
+			return id, nil
+		})
+	hc.Add(requestid.New[string, string]())
+
+	first, err := hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.Len(t, first, 32)
+	second, err := hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+
+	_, err = hc.Handle(context.Background(), "fail")
+	var handleErr *mutableware.HandleError
+	require.True(t, errors.As(err, &handleErr))
+	require.Equal(t, seen[2], handleErr.RequestID)
+	require.Equal(t, fmt.Sprintf("handleError handler=10 requestID=%s an_error", seen[2]), err.Error())
+}
+
+func TestRequestIDKeepsExisting(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			id, _ := mutableware.GetRequestIDFromContext(ctx)
+			return id, nil
+		})
+	hc.Add(requestid.New[string, string]())
+
+	ctx := mutableware.ContextWithRequestID(context.Background(), "upstream")
+	resp, err := hc.Handle(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, "upstream", resp)
+
+	override := mutableware.NewHandlerContainer[string, string]()
+	override.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			id, _ := mutableware.GetRequestIDFromContext(ctx)
+			return id, nil
+		})
+	override.Add(requestid.New[string, string](
+		requestid.OptionOverride(),
+		requestid.OptionGenerator(func() string { return "generated" })))
+	resp, err = override.Handle(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, "generated", resp)
+}
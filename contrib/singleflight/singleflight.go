@@ -0,0 +1,84 @@
+// Package singleflight provides a handler that deduplicates concurrent
+// identical requests.
+package singleflight
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+type call[Response any] struct {
+	done     chan struct{}
+	response Response
+	err      error
+}
+
+type group[Request any, Response any, K comparable] struct {
+	key   func(Request) K
+	mux   sync.Mutex
+	calls map[K]*call[Response]
+}
+
+// New creates a Handler that collapses concurrent requests with the same key
+// into a single execution of the rest of the chain. Every waiter receives the
+// same Response and error, so Responses that are pointers or contain
+// references are shared between callers.
+//
+// The shared execution runs with the context values of the first request
+// for a key, but not its cancellation. Every request, including the first,
+// stops waiting when its context ends, but none of them cancel the shared
+// execution. If the rest of the chain panics, every waiter receives a
+// *mutableware.PanicError.
+func New[Request any, Response any, K comparable](key func(Request) K) mutableware.Handler[Request, Response] {
+	return &group[Request, Response, K]{
+		key:   key,
+		calls: map[K]*call[Response]{},
+	}
+}
+
+func (g *group[Request, Response, K]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	key := g.key(request)
+
+	g.mux.Lock()
+	c, ok := g.calls[key]
+	if !ok {
+		c = &call[Response]{done: make(chan struct{})}
+		g.calls[key] = c
+		go g.run(context.WithoutCancel(ctx), key, c, request, next)
+	}
+	g.mux.Unlock()
+
+	select {
+	case <-c.done:
+		return c.response, c.err
+	case <-ctx.Done():
+		var zero Response
+		return zero, ctx.Err()
+	}
+}
+
+// run executes the rest of the chain for a call. Nothing can recover a panic
+// on its goroutine, so panics are reported to the waiters as a
+// *mutableware.PanicError.
+func (g *group[Request, Response, K]) run(ctx context.Context, key K, c *call[Response], request Request, next mutableware.CurriedHandlerFunc[Request, Response]) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := &mutableware.PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+			if path := mutableware.GetHandlerInfoFromContext(ctx); len(path) > 0 {
+				panicErr.Info = path[len(path)-1]
+			}
+			c.err = panicErr
+		}
+		g.mux.Lock()
+		delete(g.calls, key)
+		g.mux.Unlock()
+		close(c.done)
+	}()
+	c.response, c.err = next(ctx, request)
+}
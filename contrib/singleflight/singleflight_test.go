@@ -0,0 +1,122 @@
+package singleflight_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/singleflight"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingleflight(t *testing.T) {
+	calls := atomic.Int32{}
+	release := make(chan struct{})
+	hc := mutableware.NewHandlerContainer[string, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int]) (int, error) {
+			<-release
+			return int(calls.Add(1)), nil
+		})
+	hc.Add(singleflight.New[string, int](func(r string) string { return r }))
+
+	wg := sync.WaitGroup{}
+	responses := make([]int, 5)
+	for i := range responses {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := hc.Handle(context.Background(), "same")
+			require.NoError(t, err)
+			responses[i] = resp
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), calls.Load())
+	require.Equal(t, []int{1, 1, 1, 1, 1}, responses)
+
+	// later requests run again
+	resp, err := hc.Handle(context.Background(), "same")
+	require.NoError(t, err)
+	require.Equal(t, 2, resp)
+}
+
+func TestSingleflightWaiterCancel(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	hc := mutableware.NewHandlerContainer[string, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int]) (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+	hc.Add(singleflight.New[string, int](func(r string) string { return r }))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		resp, err := hc.Handle(context.Background(), "k")
+		require.NoError(t, err)
+		require.Equal(t, 1, resp)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := hc.Handle(ctx, "k")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	<-done
+}
+
+func TestSingleflightLeaderCancel(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	finished := make(chan error, 1)
+	hc := mutableware.NewHandlerContainer[string, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int]) (int, error) {
+			close(started)
+			<-release
+			finished <- ctx.Err()
+			return 1, nil
+		})
+	hc.Add(singleflight.New[string, int](func(r string) string { return r }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error)
+	go func() {
+		_, err := hc.Handle(ctx, "k")
+		leader <- err
+	}()
+	<-started
+
+	// the leader giving up doesn't cancel the shared execution.
+	cancel()
+	require.ErrorIs(t, <-leader, context.Canceled)
+	close(release)
+	require.NoError(t, <-finished)
+}
+
+func TestSingleflightPanic(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int]) (int, error) {
+			panic("boom")
+		})
+	hc.Add(singleflight.New[string, int](func(r string) string { return r }))
+
+	_, err := hc.Handle(context.Background(), "k")
+	var panicErr *mutableware.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	require.Equal(t, "boom", panicErr.Value)
+}
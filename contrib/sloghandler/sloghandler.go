@@ -0,0 +1,142 @@
+// Package sloghandler provides a slog.Handler that sends log records through
+// a HandlerContainer, so filters, enrichers, and sinks can be added and
+// removed while the process is running.
+package sloghandler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+type builtOptions struct {
+	level slog.Leveler
+}
+
+// Option is an option for the New(...) function.
+type Option func(*builtOptions)
+
+// OptionLevel sets the minimum level of records that are handled.
+// The default is slog.LevelInfo.
+func OptionLevel(level slog.Leveler) Option {
+	return func(o *builtOptions) {
+		o.level = level
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{
+		level: slog.LevelInfo,
+	}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+// scope is a group or a set of attributes added by WithGroup or WithAttrs.
+type scope struct {
+	group string
+	attrs []slog.Attr
+	prev  *scope
+}
+
+type handler struct {
+	hc    *mutableware.HandlerContainer[slog.Record, struct{}]
+	level slog.Leveler
+	scope *scope
+}
+
+// New creates a slog.Handler that sends records through hc.
+// Attributes and groups added with WithAttrs and WithGroup are applied to
+// each record before it's sent, so handlers in hc see complete records.
+func New(hc *mutableware.HandlerContainer[slog.Record, struct{}], options ...Option) slog.Handler {
+	opts := buildOptions(options)
+	return &handler{
+		hc:    hc,
+		level: opts.level,
+	}
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	if h.scope != nil {
+		record = h.resolve(record)
+	}
+	_, err := h.hc.Handle(ctx, record)
+	return err
+}
+
+// resolve makes a new record with the handler's groups and attributes
+// applied to it.
+func (h *handler) resolve(record slog.Record) slog.Record {
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	for s := h.scope; s != nil; s = s.prev {
+		if s.group == "" {
+			attrs = append(s.attrs[:len(s.attrs):len(s.attrs)], attrs...)
+		} else if len(attrs) > 0 {
+			attrs = []slog.Attr{slog.Group(s.group, attrsToAny(attrs)...)}
+		}
+	}
+	resolved := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	resolved.AddAttrs(attrs...)
+	return resolved
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(&scope{attrs: attrs})
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(&scope{group: name})
+}
+
+func (h *handler) with(s *scope) *handler {
+	s.prev = h.scope
+	return &handler{
+		hc:    h.hc,
+		level: h.level,
+		scope: s,
+	}
+}
+
+func attrsToAny(attrs []slog.Attr) []any {
+	out := make([]any, len(attrs))
+	for i, a := range attrs {
+		out[i] = a
+	}
+	return out
+}
+
+type sink struct {
+	out slog.Handler
+}
+
+// Sink creates a Handler that writes each record to out, if out is enabled
+// for its level, and then continues down the chain.
+func Sink(out slog.Handler) mutableware.Handler[slog.Record, struct{}] {
+	return &sink{out: out}
+}
+
+func (s *sink) Handle(ctx context.Context, record slog.Record, next mutableware.CurriedHandlerFunc[slog.Record, struct{}]) (struct{}, error) {
+	if s.out.Enabled(ctx, record.Level) {
+		if err := s.out.Handle(ctx, record); err != nil {
+			return struct{}{}, err
+		}
+	}
+	return next(ctx, record)
+}
@@ -0,0 +1,66 @@
+package sloghandler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/sloghandler"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	hc := mutableware.NewHandlerContainer[slog.Record, struct{}]()
+	hc.Add(sloghandler.Sink(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+	redactID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request slog.Record, next mutableware.CurriedHandlerFunc[slog.Record, struct{}]) (struct{}, error) {
+			redacted := slog.NewRecord(request.Time, request.Level, request.Message, request.PC)
+			request.Attrs(func(a slog.Attr) bool {
+				if a.Key == "password" {
+					a.Value = slog.StringValue("***")
+				}
+				redacted.AddAttrs(a)
+				return true
+			})
+			return next(ctx, redacted)
+		})
+
+	logger := slog.New(sloghandler.New(hc)).With("app", "test")
+	logger.Debug("dropped")
+	logger.Info("login", "password", "hunter2")
+	hc.Remove(redactID)
+	logger.Info("login", "password", "hunter2")
+	logger.WithGroup("req").With("id", 1).WithGroup("empty").WithGroup("user").Info("grouped", "name", "a")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+
+	decoded := make([]map[string]any, len(lines))
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal(line, &decoded[i]))
+	}
+	require.Equal(t, map[string]any{"level": "INFO", "msg": "login", "app": "test", "password": "***"}, decoded[0])
+	require.Equal(t, "hunter2", decoded[1]["password"])
+	require.Equal(t, map[string]any{
+		"level": "INFO",
+		"msg":   "grouped",
+		"app":   "test",
+		"req": map[string]any{
+			"id": float64(1),
+			"empty": map[string]any{
+				"user": map[string]any{"name": "a"},
+			},
+		},
+	}, decoded[2])
+}
@@ -0,0 +1,97 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// burst collects requests that arrive close together.
+type burst[Request any, Response any] struct {
+	exec       *execution[Response]
+	generation int
+	stop       func() bool
+	superseded chan struct{}
+
+	ctx     context.Context
+	request Request
+	next    mutableware.CurriedHandlerFunc[Request, Response]
+}
+
+type debounce[Request any, Response any] struct {
+	wait   time.Duration
+	reject bool
+	clock  mutableware.Clock
+
+	mux     sync.Mutex
+	pending *burst[Request, Response]
+}
+
+// NewDebounce creates a Handler that waits until no requests have arrived
+// for wait before running the rest of the chain once, with the latest
+// request. Earlier requests in the burst are suppressed, and receive the
+// result of that execution. Since the execution is shared, it runs with the
+// latest request's context values but not its cancellation.
+func NewDebounce[Request any, Response any](wait time.Duration, options ...Option) mutableware.Handler[Request, Response] {
+	opts := buildOptions(options)
+	return &debounce[Request, Response]{
+		wait:   wait,
+		reject: opts.reject,
+		clock:  opts.clock,
+	}
+}
+
+func (d *debounce[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	d.mux.Lock()
+	b := d.pending
+	if b == nil {
+		b = &burst[Request, Response]{
+			exec: &execution[Response]{done: make(chan struct{})},
+		}
+		d.pending = b
+	} else {
+		b.stop()
+		close(b.superseded)
+	}
+	b.generation++
+	generation := b.generation
+	superseded := make(chan struct{})
+	b.superseded = superseded
+	b.ctx, b.request, b.next = ctx, request, next
+	b.stop = d.clock.AfterFunc(d.wait, func() {
+		d.fire(b, generation)
+	})
+	d.mux.Unlock()
+
+	if d.reject {
+		select {
+		case <-superseded:
+			var zero Response
+			return zero, ErrSuppressed
+		case <-b.exec.done:
+			return b.exec.response, b.exec.err
+		case <-ctx.Done():
+			var zero Response
+			return zero, ctx.Err()
+		}
+	}
+	return b.exec.wait(ctx)
+}
+
+// fire runs the rest of the chain for a burst, unless a newer request has
+// arrived since the timer was set.
+func (d *debounce[Request, Response]) fire(b *burst[Request, Response], generation int) {
+	d.mux.Lock()
+	if d.pending != b || b.generation != generation {
+		d.mux.Unlock()
+		return
+	}
+	d.pending = nil
+	ctx, request, next := context.WithoutCancel(b.ctx), b.request, b.next
+	d.mux.Unlock()
+
+	defer close(b.exec.done)
+	b.exec.response, b.exec.err = next(ctx, request)
+}
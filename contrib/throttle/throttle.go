@@ -0,0 +1,113 @@
+// Package throttle provides handlers that limit how often the rest of the
+// chain runs, either by throttling or by debouncing requests.
+package throttle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// ErrSuppressed is returned to suppressed requests when OptionReject is used.
+var ErrSuppressed = errors.New("suppressed")
+
+type builtOptions struct {
+	reject bool
+	clock  mutableware.Clock
+}
+
+// Option is an option for the New(...) and NewDebounce(...) functions.
+type Option func(*builtOptions)
+
+// OptionReject makes suppressed requests fail with ErrSuppressed.
+// By default, suppressed requests share the result of the execution that
+// suppressed them.
+func OptionReject() Option {
+	return func(o *builtOptions) {
+		o.reject = true
+	}
+}
+
+// OptionClock makes intervals and waits follow clock instead of the real
+// time.
+func OptionClock(clock mutableware.Clock) Option {
+	return func(o *builtOptions) {
+		o.clock = clock
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{
+		clock: mutableware.SystemClock(),
+	}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+// execution is a single run of the rest of the chain.
+type execution[Response any] struct {
+	started  time.Time
+	done     chan struct{}
+	response Response
+	err      error
+}
+
+func (e *execution[Response]) wait(ctx context.Context) (Response, error) {
+	select {
+	case <-e.done:
+		return e.response, e.err
+	case <-ctx.Done():
+		var zero Response
+		return zero, ctx.Err()
+	}
+}
+
+type throttle[Request any, Response any] struct {
+	interval time.Duration
+	reject   bool
+	clock    mutableware.Clock
+
+	mux  sync.Mutex
+	last *execution[Response]
+}
+
+// New creates a Handler that runs the rest of the chain at most once per
+// interval. Requests that arrive less than interval after the last execution
+// started are suppressed, and receive that execution's response and error.
+func New[Request any, Response any](interval time.Duration, options ...Option) mutableware.Handler[Request, Response] {
+	opts := buildOptions(options)
+	return &throttle[Request, Response]{
+		interval: interval,
+		reject:   opts.reject,
+		clock:    opts.clock,
+	}
+}
+
+func (t *throttle[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	now := t.clock.Now()
+
+	t.mux.Lock()
+	if last := t.last; last != nil && now.Sub(last.started) < t.interval {
+		t.mux.Unlock()
+		if t.reject {
+			var zero Response
+			return zero, ErrSuppressed
+		}
+		return last.wait(ctx)
+	}
+	exec := &execution[Response]{
+		started: now,
+		done:    make(chan struct{}),
+	}
+	t.last = exec
+	t.mux.Unlock()
+
+	defer close(exec.done)
+	exec.response, exec.err = next(ctx, request)
+	return exec.response, exec.err
+}
@@ -0,0 +1,162 @@
+package throttle_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/throttle"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func countingContainer(calls *atomic.Int32, handler mutableware.Handler[int, int]) *mutableware.HandlerContainer[int, int] {
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			calls.Add(1)
+			return request, nil
+		})
+	hc.Add(handler)
+	return hc
+}
+
+func TestThrottle(t *testing.T) {
+	calls := atomic.Int32{}
+	clock := mutablewaretest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	hc := countingContainer(&calls, throttle.New[int, int](time.Minute, throttle.OptionClock(clock)))
+
+	resp, err := hc.Handle(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, resp)
+
+	resp, err = hc.Handle(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, 1, resp)
+	require.Equal(t, int32(1), calls.Load())
+
+	clock.Advance(time.Minute)
+	resp, err = hc.Handle(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, resp)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestThrottleReject(t *testing.T) {
+	calls := atomic.Int32{}
+	hc := countingContainer(&calls, throttle.New[int, int](time.Hour, throttle.OptionReject()))
+
+	_, err := hc.Handle(context.Background(), 1)
+	require.NoError(t, err)
+	_, err = hc.Handle(context.Background(), 2)
+	require.ErrorIs(t, err, throttle.ErrSuppressed)
+}
+
+// schedulingClock is a FakeClock that reports each call to AfterFunc, so
+// tests can tell when a request has reached the debouncer.
+type schedulingClock struct {
+	*mutablewaretest.FakeClock
+	scheduled chan struct{}
+}
+
+func newSchedulingClock() *schedulingClock {
+	return &schedulingClock{
+		FakeClock: mutablewaretest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		scheduled: make(chan struct{}, 10),
+	}
+}
+
+func (c *schedulingClock) AfterFunc(d time.Duration, f func()) func() bool {
+	stop := c.FakeClock.AfterFunc(d, f)
+	c.scheduled <- struct{}{}
+	return stop
+}
+
+func TestDebounce(t *testing.T) {
+	calls := atomic.Int32{}
+	clock := newSchedulingClock()
+	hc := countingContainer(&calls, throttle.NewDebounce[int, int](time.Minute, throttle.OptionClock(clock)))
+
+	wg := sync.WaitGroup{}
+	responses := make([]int, 3)
+	for i := range responses {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := hc.Handle(context.Background(), i)
+			require.NoError(t, err)
+			responses[i] = resp
+		}()
+		<-clock.scheduled
+		clock.Advance(time.Second)
+	}
+	require.Equal(t, int32(0), calls.Load())
+	clock.Advance(time.Minute)
+	wg.Wait()
+
+	require.Equal(t, int32(1), calls.Load())
+	require.Equal(t, []int{2, 2, 2}, responses)
+}
+
+func TestDebounceReject(t *testing.T) {
+	calls := atomic.Int32{}
+	clock := newSchedulingClock()
+	hc := countingContainer(&calls, throttle.NewDebounce[int, int](time.Minute, throttle.OptionReject(), throttle.OptionClock(clock)))
+
+	first := make(chan error)
+	go func() {
+		_, err := hc.Handle(context.Background(), 1)
+		first <- err
+	}()
+	<-clock.scheduled
+
+	second := make(chan error)
+	go func() {
+		resp, err := hc.Handle(context.Background(), 2)
+		require.Equal(t, 2, resp)
+		second <- err
+	}()
+	<-clock.scheduled
+	require.ErrorIs(t, <-first, throttle.ErrSuppressed)
+
+	clock.Advance(time.Minute)
+	require.NoError(t, <-second)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestDebounceDetached(t *testing.T) {
+	clock := newSchedulingClock()
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			return request, ctx.Err()
+		})
+	hc.Add(throttle.NewDebounce[int, int](time.Minute, throttle.OptionClock(clock)))
+
+	first := make(chan mutableware.Result[int], 1)
+	go func() {
+		resp, err := hc.Handle(context.Background(), 1)
+		first <- mutableware.Result[int]{Response: resp, Err: err}
+	}()
+	<-clock.scheduled
+
+	// the latest caller gives up, but the earlier one still gets a result.
+	ctx, cancel := context.WithCancel(context.Background())
+	second := make(chan error, 1)
+	go func() {
+		_, err := hc.Handle(ctx, 2)
+		second <- err
+	}()
+	<-clock.scheduled
+	cancel()
+	require.ErrorIs(t, <-second, context.Canceled)
+
+	clock.Advance(time.Minute)
+	result := <-first
+	require.NoError(t, result.Err)
+	require.Equal(t, 2, result.Response)
+}
@@ -0,0 +1,91 @@
+// Package validate provides a handler that validates requests before they
+// reach the rest of the chain.
+package validate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// Validator is implemented by requests that can validate themselves.
+type Validator interface {
+	Validate() error
+}
+
+// Error is returned when a request fails validation.
+// It's never retryable, since retrying an invalid request won't fix it.
+type Error struct {
+	Err error
+}
+
+func (e *Error) Error() string {
+	return "invalidRequest: " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Retryable() bool {
+	return false
+}
+
+type builtOptions struct {
+	validate any
+}
+
+// Option is an option for the New(...) function.
+type Option func(*builtOptions)
+
+// OptionValidator validates requests with validate instead of their
+// Validate() method.
+// The Request type must match the handler's, or New panics.
+func OptionValidator[Request any](validate func(Request) error) Option {
+	return func(o *builtOptions) {
+		o.validate = validate
+	}
+}
+
+func buildOptions(opts []Option) *builtOptions {
+	built := &builtOptions{}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+type validateHandler[Request any, Response any] struct {
+	validate func(Request) error
+}
+
+// New creates a Handler that validates each request before calling next.
+// Requests are validated with the function passed to OptionValidator, or
+// with their Validate() method if they implement Validator. Requests that
+// can't be validated either way are let through.
+// Invalid requests fail with an *Error.
+func New[Request any, Response any](options ...Option) mutableware.Handler[Request, Response] {
+	opts := buildOptions(options)
+	validate, ok := opts.validate.(func(Request) error)
+	if opts.validate != nil && !ok {
+		panic(fmt.Sprintf("validate: OptionValidator needs a %T, not a %T", validate, opts.validate))
+	}
+	return &validateHandler[Request, Response]{
+		validate: validate,
+	}
+}
+
+func (v *validateHandler[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	var err error
+	if v.validate != nil {
+		err = v.validate(request)
+	} else if validator, ok := any(request).(Validator); ok {
+		err = validator.Validate()
+	}
+	if err != nil {
+		var zero Response
+		return zero, &Error{Err: err}
+	}
+	return next(ctx, request)
+}
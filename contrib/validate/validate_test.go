@@ -0,0 +1,74 @@
+package validate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/contrib/validate"
+	"github.com/stretchr/testify/require"
+)
+
+type order struct {
+	Quantity int
+}
+
+func (o order) Validate() error {
+	if o.Quantity <= 0 {
+		return errors.New("quantity must be positive")
+	}
+	return nil
+}
+
+func terminal[Request any](ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, string]) (string, error) {
+	return "ok", nil
+}
+
+func TestValidateMethod(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[order, string]()
+	hc.AddAnonymousHandler(terminal[order])
+	hc.Add(validate.New[order, string]())
+
+	resp, err := hc.Handle(context.Background(), order{Quantity: 1})
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+
+	_, err = hc.Handle(context.Background(), order{})
+	var validationErr *validate.Error
+	require.ErrorAs(t, err, &validationErr)
+	require.EqualError(t, validationErr.Err, "quantity must be positive")
+	require.False(t, mutableware.IsRetryable(context.Background(), err))
+}
+
+func TestValidatorFunc(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(terminal[string])
+	hc.Add(validate.New[string, string](validate.OptionValidator(func(r string) error {
+		if r == "" {
+			return errors.New("empty")
+		}
+		return nil
+	})))
+
+	_, err := hc.Handle(context.Background(), "x")
+	require.NoError(t, err)
+	_, err = hc.Handle(context.Background(), "")
+	var validationErr *validate.Error
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestValidatorTypeMismatch(t *testing.T) {
+	require.Panics(t, func() {
+		validate.New[string, string](validate.OptionValidator(func(r int) error { return nil }))
+	})
+}
+
+func TestNoValidator(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, string]()
+	hc.AddAnonymousHandler(terminal[int])
+	hc.Add(validate.New[int, string]())
+
+	_, err := hc.Handle(context.Background(), 0)
+	require.NoError(t, err)
+}
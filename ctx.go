@@ -4,22 +4,98 @@ import "context"
 
 type ctxKeyType int
 
-const ctxKey = ctxKeyType(123)
+const (
+	ctxKey               = ctxKeyType(123)
+	streamCtxKey         = ctxKeyType(124)
+	errorCollectorCtxKey = ctxKeyType(125)
+	classifierCtxKey     = ctxKeyType(126)
+	requestIDCtxKey      = ctxKeyType(127)
+	callNextCtxKey       = ctxKeyType(128)
+	overridesCtxKey      = ctxKeyType(129)
+	traceCtxKey          = ctxKeyType(130)
+)
+
+// handlerInfoCtx is a context that pushes a handler onto the handler stack
+// of its parent. The stack is a persistent linked list, so pushing a handler
+// is a single allocation no matter how deep the chain is. Nodes are never
+// modified, so handlers that fan out and call next from several goroutines
+// can't corrupt each other's stacks. Contexts can be retained by handlers,
+// so they can't be pooled.
+type handlerInfoCtx struct {
+	context.Context
+	info   HandlerInfo
+	parent *handlerInfoCtx
+	depth  int
+	// trace is the request's execution trace, if it has one. It's carried
+	// down the stack so handlers don't have to search the context for it.
+	trace *executionTrace
+}
+
+func (c *handlerInfoCtx) Value(key any) any {
+	if key == ctxKey {
+		return c
+	}
+	return c.Context.Value(key)
+}
 
 func contextWithHandlerInfo(parent context.Context, info HandlerInfo) context.Context {
-	if stack, ok := (parent.Value(ctxKey)).([]HandlerInfo); ok {
-		return context.WithValue(parent, ctxKey, append(stack, info))
+	top, _ := (parent.Value(ctxKey)).(*handlerInfoCtx)
+	depth := 1
+	var trace *executionTrace
+	if top != nil {
+		depth = top.depth + 1
+	}
+	if top != nil && parent == context.Context(top) {
+		trace = top.trace
 	} else {
-		return context.WithValue(parent, ctxKey, []HandlerInfo{info})
+		// a newer trace may have been added on top of the handler's
+		// context, like by HandleDetailed from inside a handler.
+		trace, _ = (parent.Value(traceCtxKey)).(*executionTrace)
+	}
+	return &handlerInfoCtx{
+		Context: parent,
+		info:    info,
+		parent:  top,
+		depth:   depth,
+		trace:   trace,
 	}
 }
 
 // GetHandlerInfoFromContext returns the current stack of handlers for a request.
 // The latest handler to be invoked will be last in the slice.
+// The slice is a copy, so it's safe to modify.
 func GetHandlerInfoFromContext(ctx context.Context) []HandlerInfo {
-	if stack, ok := (ctx.Value(ctxKey)).([]HandlerInfo); ok {
-		return stack
-	} else {
+	top, ok := (ctx.Value(ctxKey)).(*handlerInfoCtx)
+	if !ok {
 		return []HandlerInfo{}
 	}
+	stack := make([]HandlerInfo, top.depth)
+	for node := top; node != nil; node = node.parent {
+		stack[node.depth-1] = node.info
+	}
+	return stack
+}
+
+// GetCurrentHandlerInfoFromContext returns the handler that the context was
+// passed to, which is the last handler in GetHandlerInfoFromContext.
+// It returns false if the context wasn't passed to a handler.
+func GetCurrentHandlerInfoFromContext(ctx context.Context) (HandlerInfo, bool) {
+	top, ok := (ctx.Value(ctxKey)).(*handlerInfoCtx)
+	if !ok {
+		return HandlerInfo{}, false
+	}
+	return top.info, true
+}
+
+// ContextWithRequestID attaches a request ID to the context, so that
+// handlers can correlate their work with the request. HandleError includes
+// it too.
+func ContextWithRequestID(parent context.Context, id string) context.Context {
+	return context.WithValue(parent, requestIDCtxKey, id)
+}
+
+// GetRequestIDFromContext returns the request ID attached to the context.
+func GetRequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := (ctx.Value(requestIDCtxKey)).(string)
+	return id, ok
 }
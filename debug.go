@@ -0,0 +1,100 @@
+package mutableware
+
+import (
+	"fmt"
+	"time"
+)
+
+// DebugIssueKind is the kind of suspicious usage found by
+// ContainerOptionDebug.
+type DebugIssueKind int
+
+const (
+	// DebugIssueLiveMutation is a change to a live chain (see
+	// ContainerOptionLiveChain) while requests were in flight. Those
+	// requests may or may not see the change, depending on where they are
+	// in the chain.
+	DebugIssueLiveMutation DebugIssueKind = iota + 1
+	// DebugIssueLongLock is a mutation or chain rebuild that held the
+	// container's lock for longer than allowed. Requests that need the
+	// lock are blocked for that long.
+	DebugIssueLongLock
+)
+
+// DebugIssue describes suspicious usage found by ContainerOptionDebug.
+type DebugIssue struct {
+	Kind DebugIssueKind
+	// InFlight is the number of requests that were in flight.
+	InFlight int64
+	// Held is how long the lock was held, for DebugIssueLongLock.
+	Held time.Duration
+}
+
+func (d *DebugIssue) Error() string {
+	switch d.Kind {
+	case DebugIssueLiveMutation:
+		return fmt.Sprintf("live chain changed with %d requests in flight", d.InFlight)
+	case DebugIssueLongLock:
+		return fmt.Sprintf("container lock held for %s", d.Held)
+	}
+	return fmt.Sprintf("debug issue %d", d.Kind)
+}
+
+type debugOptions struct {
+	maxLockHold time.Duration
+	onIssue     func(*DebugIssue)
+}
+
+// lock takes the lock. Release it with unlock.
+func (hc *HandlerContainer[Request, Response]) lock() {
+	hc.mux.Lock()
+	if hc.opts.debug != nil && hc.opts.debug.maxLockHold > 0 {
+		hc.lockedAt = hc.opts.clock.Now()
+	}
+}
+
+// checkMutation records a DebugIssueLiveMutation if requests are in flight.
+// The lock must be held.
+func (hc *HandlerContainer[Request, Response]) checkMutation() {
+	if hc.opts.debug == nil || !hc.opts.liveChain {
+		return
+	}
+	if inflight := hc.inflight.Load(); inflight > 0 {
+		hc.issues = append(hc.issues, &DebugIssue{
+			Kind:     DebugIssueLiveMutation,
+			InFlight: inflight,
+		})
+	}
+}
+
+// takeIssues returns the issues found while the lock was held, including
+// holding it for too long. The lock must be held.
+func (hc *HandlerContainer[Request, Response]) takeIssues() []*DebugIssue {
+	if hc.opts.debug == nil {
+		return nil
+	}
+	issues := hc.issues
+	hc.issues = nil
+	if !hc.lockedAt.IsZero() {
+		if held := hc.opts.clock.Now().Sub(hc.lockedAt); held > hc.opts.debug.maxLockHold {
+			issues = append(issues, &DebugIssue{
+				Kind:     DebugIssueLongLock,
+				InFlight: hc.inflight.Load(),
+				Held:     held,
+			})
+		}
+		hc.lockedAt = time.Time{}
+	}
+	return issues
+}
+
+// report sends issues to the debug callback, or panics with the first one if
+// there isn't a callback. The lock must not be held.
+func (hc *HandlerContainer[Request, Response]) report(issues []*DebugIssue) {
+	for _, issue := range issues {
+		if hc.opts.debug.onIssue == nil {
+			panic(issue)
+		}
+		hc.opts.debug.onIssue(issue)
+	}
+}
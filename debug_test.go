@@ -0,0 +1,75 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugLiveMutation(t *testing.T) {
+	var issues []*mutableware.DebugIssue
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionLiveChain(),
+		mutableware.ContainerOptionDebug(0, func(issue *mutableware.DebugIssue) {
+			issues = append(issues, issue)
+		}))
+	hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		hc.AddAnonymousHandler(nil)
+		return next(ctx, request)
+	})
+	require.Empty(t, issues)
+
+	_, err := hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.Equal(t, []*mutableware.DebugIssue{{
+		Kind:     mutableware.DebugIssueLiveMutation,
+		InFlight: 1,
+	}}, issues)
+}
+
+func TestDebugSnapshotMutation(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionDebug(0, nil))
+	hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		hc.AddAnonymousHandler(nil)
+		return next(ctx, request)
+	})
+
+	// without a live chain, the change doesn't affect in-flight requests.
+	require.NotPanics(t, func() {
+		_, _ = hc.Handle(context.Background(), "a")
+	})
+}
+
+type slowInit struct {
+	clock *mutablewaretest.FakeClock
+}
+
+func (s *slowInit) Init() error {
+	s.clock.Advance(time.Second)
+	return nil
+}
+
+func (s *slowInit) Handle(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+	return next(ctx, request)
+}
+
+func TestDebugLongLock(t *testing.T) {
+	clock := mutablewaretest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionClock(clock),
+		mutableware.ContainerOptionDebug(100*time.Millisecond, nil))
+	hc.AddAnonymousHandler(nil)
+
+	require.PanicsWithError(t, "container lock held for 1s", func() {
+		hc.Add(&slowInit{clock: clock})
+	})
+
+	// the lock was released before panicking.
+	require.Len(t, hc.Layout().Chain, 2)
+	_, err := hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+}
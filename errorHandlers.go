@@ -0,0 +1,43 @@
+package mutableware
+
+import "context"
+
+// Failure describes a request that the main chain failed to handle.
+// It is the Request type of a container's error handlers.
+type Failure[Request any, Response any] struct {
+	Request  Request
+	Response Response
+	Err      error
+}
+
+// failureHandler hides the type of the error handler container,
+// since a HandlerContainer can't directly refer to a container of
+// its own Failures.
+type failureHandler[Request any, Response any] interface {
+	Handle(ctx context.Context, failure Failure[Request, Response]) (Response, error)
+}
+
+// ErrorHandlers returns the container of error handlers attached to hc,
+// creating it if needed.
+//
+// When the main chain of hc returns an error, the error handlers are sent a
+// Failure describing it, and their result is returned from hc.Handle instead.
+// Error handlers can replace the response, transform the error, or swallow
+// it by returning a nil error. If every error handler falls through, the
+// original response and error are returned.
+func ErrorHandlers[Request any, Response any](hc *HandlerContainer[Request, Response]) *HandlerContainer[Failure[Request, Response], Response] {
+	hc.lock()
+	defer hc.unlock()
+
+	if hc.errorHandlers == nil {
+		errorHandlers := NewHandlerContainer[Failure[Request, Response], Response]()
+		errorHandlers.terminal = failureTerminal[Request, Response]
+		hc.errorHandlers = errorHandlers
+		hc.invalidate()
+	}
+	return hc.errorHandlers.(*HandlerContainer[Failure[Request, Response], Response])
+}
+
+func failureTerminal[Request any, Response any](ctx context.Context, failure Failure[Request, Response]) (Response, error) {
+	return failure.Response, failure.Err
+}
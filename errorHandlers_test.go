@@ -0,0 +1,49 @@
+package mutableware_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorHandlers(t *testing.T) {
+	expectedErr := fmt.Errorf("an_error")
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			if request == "fail" {
+				return "partial", expectedErr
+			}
+			return "ok", nil
+		})
+
+	errorHandlers := mutableware.ErrorHandlers(hc)
+	require.Same(t, errorHandlers, mutableware.ErrorHandlers(hc))
+
+	// with no error handlers, the failure passes through.
+	resp, err := hc.Handle(context.Background(), "fail")
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, "partial", resp)
+
+	swallowID := errorHandlers.AddAnonymousHandler(
+		func(ctx context.Context, failure mutableware.Failure[string, string], next mutableware.CurriedHandlerFunc[mutableware.Failure[string, string], string]) (string, error) {
+			require.Equal(t, "fail", failure.Request)
+			require.Equal(t, "partial", failure.Response)
+			return "recovered", nil
+		})
+
+	resp, err = hc.Handle(context.Background(), "fail")
+	require.NoError(t, err)
+	require.Equal(t, "recovered", resp)
+
+	resp, err = hc.Handle(context.Background(), "pass")
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+
+	errorHandlers.Remove(swallowID)
+	_, err = hc.Handle(context.Background(), "fail")
+	require.ErrorIs(t, err, expectedErr)
+}
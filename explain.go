@@ -0,0 +1,90 @@
+package mutableware
+
+import (
+	"context"
+	"slices"
+)
+
+// Explainer is an optional interface for Handlers.
+// Explain reports whether the handler would act on the request if it were
+// sent to Handle. It must not have side effects.
+type Explainer[Request any] interface {
+	Explain(ctx context.Context, request Request) bool
+}
+
+// Explanation describes how a single handler would treat a request.
+type Explanation struct {
+	Info HandlerInfo
+	// Active is true if the handler would act on the request.
+	// Handlers that don't implement Explainer are always active.
+	Active bool
+	// Explained is true if the handler implements Explainer.
+	Explained bool
+}
+
+// Explain reports which handlers would act on the request without actually
+// running them. Explanations are in the order the handlers would be invoked.
+// Handlers that short-circuit the chain can't be detected, so every handler
+// is listed.
+func (hc *HandlerContainer[Request, Response]) Explain(ctx context.Context, request Request) []Explanation {
+	hc.mux.RLock()
+	stack := append(slices.Clone(hc.bottom), hc.stack...)
+	stack = append(stack, hc.pinned...)
+	hc.mux.RUnlock()
+
+	explanations := make([]Explanation, 0, len(stack))
+	for i := len(stack) - 1; i >= 0; i-- {
+		handler := stack[i]
+		handlerCtx := contextWithHandlerInfo(ctx, handler.info)
+		active, explained := explain(handlerCtx, handler.Handler, request)
+		explanations = append(explanations, Explanation{
+			Info:      handler.info,
+			Active:    active,
+			Explained: explained,
+		})
+	}
+	return explanations
+}
+
+func explain[Request any, Response any](ctx context.Context, handler Handler[Request, Response], request Request) (active bool, explained bool) {
+	if explainer, ok := handler.(Explainer[Request]); ok {
+		return explainer.Explain(ctx, request), true
+	}
+	return true, false
+}
+
+// Explain reports shadow handlers as inactive, since they never affect the chain.
+func (s *shadowHandler[Request, Response]) Explain(ctx context.Context, request Request) bool {
+	return false
+}
+
+// Explain reports the pair as active if either handler is.
+func (c *canaryHandler[Request, Response]) Explain(ctx context.Context, request Request) bool {
+	oldActive, _ := explain(ctx, c.old, request)
+	candidateActive, _ := explain(ctx, c.candidate, request)
+	return oldActive || candidateActive
+}
+
+// Explain forwards to the retried handler.
+func (r *retryHandler[Request, Response]) Explain(ctx context.Context, request Request) bool {
+	active, _ := explain(ctx, r.Handler, request)
+	return active
+}
+
+// Explain forwards to the wrapped handler.
+func (m *mapErrorHandler[Request, Response]) Explain(ctx context.Context, request Request) bool {
+	active, _ := explain(ctx, m.Handler, request)
+	return active
+}
+
+// Explain forwards to the handler being timed.
+func (t *timeoutHandler[Request, Response]) Explain(ctx context.Context, request Request) bool {
+	active, _ := explain(ctx, t.Handler, request)
+	return active
+}
+
+// Explain forwards to the limited handler.
+func (c *concurrencyHandler[Request, Response]) Explain(ctx context.Context, request Request) bool {
+	active, _ := explain(ctx, c.Handler, request)
+	return active
+}
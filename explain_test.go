@@ -0,0 +1,51 @@
+package mutableware_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+// prefixHandler only acts on requests with a given prefix.
+type prefixHandler struct {
+	prefix string
+}
+
+func (p *prefixHandler) Handle(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+	if strings.HasPrefix(request, p.prefix) {
+		return p.prefix, nil
+	}
+	return next(ctx, request)
+}
+
+func (p *prefixHandler) Explain(ctx context.Context, request string) bool {
+	return strings.HasPrefix(request, p.prefix)
+}
+
+func TestExplain(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	anonID := hc.AddAnonymousHandler(nil)
+	aID := hc.Add(&prefixHandler{prefix: "a"}, mutableware.AddOptionName("a"))
+	bID := hc.Add(&prefixHandler{prefix: "b"}, mutableware.AddOptionName("b"))
+
+	require.Equal(t, []mutableware.Explanation{
+		{Info: mutableware.HandlerInfo{ID: bID, Name: "b"}, Active: false, Explained: true},
+		{Info: mutableware.HandlerInfo{ID: aID, Name: "a"}, Active: true, Explained: true},
+		{Info: mutableware.HandlerInfo{ID: anonID}, Active: true, Explained: false},
+	}, hc.Explain(context.Background(), "apple"))
+}
+
+func TestExplainWrapped(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	timeoutID := hc.Add(&prefixHandler{prefix: "a"}, mutableware.AddOptionTimeout(time.Hour))
+	concurrencyID := hc.Add(&prefixHandler{prefix: "b"}, mutableware.AddOptionMaxConcurrency(1, 0))
+
+	require.Equal(t, []mutableware.Explanation{
+		{Info: mutableware.HandlerInfo{ID: concurrencyID}, Active: false, Explained: true},
+		{Info: mutableware.HandlerInfo{ID: timeoutID}, Active: true, Explained: true},
+	}, hc.Explain(context.Background(), "apple"))
+}
@@ -0,0 +1,24 @@
+package mutableware
+
+import "expvar"
+
+// publishExpvar publishes the state of the container under the name.
+func (hc *HandlerContainer[Request, Response]) publishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		chain := hc.ChainStats()
+
+		stats := map[string]HandlerStats{}
+		for _, handlerStats := range hc.Stats() {
+			stats[handlerStats.Info.String()] = handlerStats
+		}
+		return map[string]any{
+			"handlers":     chain.Handlers,
+			"mutations":    chain.Mutations,
+			"lastMutation": chain.LastMutation,
+			"inFlight":     hc.inflight.Load(),
+			"paused":       hc.paused.Load() != nil,
+			"closed":       hc.closed.Load(),
+			"stats":        stats,
+		}
+	}))
+}
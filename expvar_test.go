@@ -0,0 +1,34 @@
+package mutableware_test
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpvar(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionExpvar("mutableware_test"))
+	id := hc.AddAnonymousHandler(nil, mutableware.AddOptionName("passthrough"))
+	_, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+
+	// a child shouldn't try to publish under the same name.
+	mutableware.NewChildContainer(hc)
+
+	published := expvar.Get("mutableware_test")
+	require.NotNil(t, published)
+	var state struct {
+		Handlers int
+		InFlight int64
+		Paused   bool
+		Closed   bool
+		Stats    map[string]mutableware.HandlerStats
+	}
+	require.NoError(t, json.Unmarshal([]byte(published.String()), &state))
+	require.Equal(t, 1, state.Handlers)
+	require.Equal(t, uint64(1), state.Stats[mutableware.HandlerInfo{ID: id, Name: "passthrough"}.String()].Calls)
+}
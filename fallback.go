@@ -0,0 +1,23 @@
+package mutableware
+
+import "context"
+
+// handleFallbacks sends a request that failed in the main chain through the
+// fallback handlers.
+func (hc *HandlerContainer[Request, Response]) handleFallbacks(ctx context.Context, request Request, fallbacks []identifiedHandler[Request, Response], response Response, err error) (Response, error) {
+	if len(fallbacks) == 0 {
+		return response, err
+	}
+
+	curriedHandler := func(cx context.Context, msg Request) (Response, error) {
+		return response, err
+	}
+	for _, handler := range fallbacks {
+		handler := handler
+		prevHandler := curriedHandler
+		curriedHandler = func(cx context.Context, msg Request) (Response, error) {
+			return hc.invoke(cx, msg, handler, prevHandler)
+		}
+	}
+	return curriedHandler(ctx, request)
+}
@@ -0,0 +1,40 @@
+package mutableware_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallback(t *testing.T) {
+	expectedErr := fmt.Errorf("an_error")
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			if request == "fail" {
+				return "", expectedErr
+			}
+			return "ok", nil
+		})
+	fallbackID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			_, err := next(ctx, request)
+			require.ErrorIs(t, err, expectedErr)
+			return "degraded " + request, nil
+		}, mutableware.AddOptionFallback())
+
+	resp, err := hc.Handle(context.Background(), "pass")
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+
+	resp, err = hc.Handle(context.Background(), "fail")
+	require.NoError(t, err)
+	require.Equal(t, "degraded fail", resp)
+
+	hc.Remove(fallbackID)
+	_, err = hc.Handle(context.Background(), "fail")
+	require.ErrorIs(t, err, expectedErr)
+}
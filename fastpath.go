@@ -0,0 +1,28 @@
+package mutableware
+
+import (
+	"context"
+	"time"
+)
+
+// fastPathHandler is run instead of the rest of the chain when the
+// request is about to run out of time.
+type fastPathHandler[Request any, Response any] struct {
+	identifiedHandler[Request, Response]
+	threshold time.Duration
+}
+
+// due is true if the context deadline is within the threshold.
+func (f fastPathHandler[Request, Response]) due(ctx context.Context, clock Clock) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && until(clock, deadline) < f.threshold
+}
+
+// currentFastPath returns the newest fast-path handler.
+// The lock must be held.
+func (hc *HandlerContainer[Request, Response]) currentFastPath() (fastPathHandler[Request, Response], bool) {
+	if len(hc.fastPaths) == 0 {
+		return fastPathHandler[Request, Response]{}, false
+	}
+	return hc.fastPaths[len(hc.fastPaths)-1], true
+}
@@ -0,0 +1,55 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFastPath(t *testing.T) {
+	ran := []string{}
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			ran = append(ran, "expensive")
+			return "fresh", nil
+		})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			ran = append(ran, "slow")
+			time.Sleep(20 * time.Millisecond)
+			return next(ctx, request)
+		})
+	fastID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			ran = append(ran, "fast")
+			return "stale", nil
+		}, mutableware.AddOptionFastPath(50*time.Millisecond))
+
+	// plenty of time
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	resp, err := hc.Handle(ctx, "req")
+	require.NoError(t, err)
+	require.Equal(t, "fresh", resp)
+	require.Equal(t, []string{"slow", "expensive"}, ran)
+
+	// runs out of time after the slow handler
+	ran = []string{}
+	ctx, cancel = context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+	resp, err = hc.Handle(ctx, "req")
+	require.NoError(t, err)
+	require.Equal(t, "stale", resp)
+	require.Equal(t, []string{"slow", "fast"}, ran)
+
+	// no deadline
+	hc.Remove(fastID)
+	ran = []string{}
+	resp, err = hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "fresh", resp)
+}
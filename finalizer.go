@@ -0,0 +1,57 @@
+package mutableware
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// FinalizerFunc is run after a request has been handled, with the response
+// and error that Handle is about to return.
+type FinalizerFunc[Request any, Response any] func(ctx context.Context, request Request, response Response, err error)
+
+type finalizer[Request any, Response any] struct {
+	id HandlerID
+	fn FinalizerFunc[Request, Response]
+}
+
+// AddFinalizer adds a function that runs after every request the container
+// handles, no matter which handler finished it or whether it failed.
+// Finalizers see the result after fallback and error handlers have run, and
+// can't change it. If a handler panics, finalizers are passed a *PanicError
+// before the panic continues. Newer finalizers run first.
+// Retain the returned HandlerID if you need to Remove() the finalizer later.
+// If the container is full (see ContainerOptionMaxHandlers), the finalizer
+// isn't added and 0 is returned.
+func (hc *HandlerContainer[Request, Response]) AddFinalizer(fn FinalizerFunc[Request, Response]) HandlerID {
+	hc.lock()
+	defer hc.unlock()
+	defer hc.invalidate()
+
+	if !hc.hasRoomFor(1) {
+		return HandlerID(0)
+	}
+	id := HandlerID(hc.nextID)
+	hc.nextID = hc.nextID + 1
+	hc.finalizers = append(hc.finalizers, finalizer[Request, Response]{id: id, fn: fn})
+	return id
+}
+
+// handleFinally handles the request, and then runs the finalizers.
+func (hc *HandlerContainer[Request, Response]) handleFinally(ctx context.Context, request Request, snap *snapshot[Request, Response]) (response Response, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			err = &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+		}
+		for i := len(snap.finalizers) - 1; i >= 0; i-- {
+			snap.finalizers[i].fn(ctx, request, response, err)
+		}
+		if r != nil {
+			panic(r)
+		}
+	}()
+	return hc.handle(ctx, request, snap)
+}
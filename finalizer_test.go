@@ -0,0 +1,64 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinalizer(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	failure := errors.New("failed")
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			switch request {
+			case "fail":
+				return "partial", failure
+			case "panic":
+				panic("boom")
+			}
+			return "ok", nil
+		})
+	// this short-circuits every request, and the finalizers still run.
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return next(ctx, request)
+		})
+
+	order := []string{}
+	var lastResponse string
+	var lastErr error
+	hc.AddFinalizer(func(ctx context.Context, request string, response string, err error) {
+		order = append(order, "first")
+		lastResponse, lastErr = response, err
+	})
+	secondID := hc.AddFinalizer(func(ctx context.Context, request string, response string, err error) {
+		order = append(order, "second")
+	})
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "ok", lastResponse)
+	require.NoError(t, lastErr)
+	require.Equal(t, []string{"second", "first"}, order)
+
+	_, err = hc.Handle(context.Background(), "fail")
+	require.ErrorIs(t, err, failure)
+	require.Equal(t, "partial", lastResponse)
+	require.ErrorIs(t, lastErr, failure)
+
+	require.PanicsWithValue(t, "boom", func() {
+		_, _ = hc.Handle(context.Background(), "panic")
+	})
+	var panicErr *mutableware.PanicError
+	require.ErrorAs(t, lastErr, &panicErr)
+	require.Equal(t, "boom", panicErr.Value)
+
+	hc.Remove(secondID)
+	order = order[:0]
+	_, _ = hc.Handle(context.Background(), "req")
+	require.Equal(t, []string{"first"}, order)
+}
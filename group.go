@@ -0,0 +1,91 @@
+package mutableware
+
+// GroupID identifies a group of handlers added with AddGroup.
+type GroupID uint64
+
+type groupMember[Request any, Response any] struct {
+	handler Handler[Request, Response]
+	options []AddOption
+}
+
+// Group is a set of handlers that are added to and removed from a
+// container together. Build a group once, then add it to any number of
+// containers with AddGroup.
+type Group[Request any, Response any] struct {
+	members []groupMember[Request, Response]
+}
+
+// NewGroup creates an empty Group.
+func NewGroup[Request any, Response any]() *Group[Request, Response] {
+	return &Group[Request, Response]{}
+}
+
+// Add a handler to the group. Handlers are added to containers in the same
+// order they're added to the group, so newer handlers are invoked first.
+func (g *Group[Request, Response]) Add(handler Handler[Request, Response], options ...AddOption) *Group[Request, Response] {
+	g.members = append(g.members, groupMember[Request, Response]{
+		handler: handler,
+		options: options,
+	})
+	return g
+}
+
+// AddAnonymousHandler adds a handler function to the group. See Add.
+func (g *Group[Request, Response]) AddAnonymousHandler(handlerFn HandlerFunc[Request, Response], options ...AddOption) *Group[Request, Response] {
+	return g.Add(handlerFn.Handler(), options...)
+}
+
+// AddGroup adds every handler in the group to the container at once, so
+// requests never see part of a group. Retain the returned GroupID to
+// remove them all later with RemoveGroup.
+// If the container doesn't have room for the whole group (see
+// ContainerOptionMaxHandlers), or any of its handlers fail to initialize
+// (see Initializer), none of it is added and 0 is returned.
+func (hc *HandlerContainer[Request, Response]) AddGroup(group *Group[Request, Response]) GroupID {
+	hc.lock()
+	defer hc.unlock()
+	defer hc.invalidate()
+
+	if !hc.hasRoomFor(len(group.members)) {
+		return GroupID(0)
+	}
+
+	ids := make([]HandlerID, 0, len(group.members))
+	for _, member := range group.members {
+		handlerID, err := hc.add(member.handler, member.options)
+		if err != nil {
+			for _, added := range ids {
+				hc.remove(added)
+			}
+			return GroupID(0)
+		}
+		ids = append(ids, handlerID)
+	}
+
+	id := GroupID(hc.nextID)
+	hc.nextID = hc.nextID + 1
+	if hc.groups == nil {
+		hc.groups = map[GroupID][]HandlerID{}
+	}
+	hc.groups[id] = ids
+	return id
+}
+
+// GroupHandlers returns the IDs of the handlers that were added with the group.
+func (hc *HandlerContainer[Request, Response]) GroupHandlers(id GroupID) []HandlerID {
+	hc.mux.RLock()
+	defer hc.mux.RUnlock()
+	return append([]HandlerID{}, hc.groups[id]...)
+}
+
+// RemoveGroup removes every handler that was added with the group at once.
+func (hc *HandlerContainer[Request, Response]) RemoveGroup(id GroupID) {
+	hc.lock()
+	defer hc.unlock()
+	defer hc.invalidate()
+
+	for _, handlerID := range hc.groups[id] {
+		hc.remove(handlerID)
+	}
+	delete(hc.groups, id)
+}
@@ -0,0 +1,47 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request, nil
+		})
+	hc.AddAnonymousHandler(appendHandler("x"))
+
+	feature := mutableware.NewGroup[string, string]().
+		AddAnonymousHandler(appendHandler("a")).
+		AddAnonymousHandler(appendHandler("b"), mutableware.AddOptionName("b"))
+
+	groupID := hc.AddGroup(feature)
+	hc.AddAnonymousHandler(appendHandler("y"))
+	require.Len(t, hc.GroupHandlers(groupID), 2)
+
+	resp, err := hc.Handle(context.Background(), ">")
+	require.NoError(t, err)
+	require.Equal(t, ">ybax", resp)
+
+	hc.RemoveGroup(groupID)
+	require.Empty(t, hc.GroupHandlers(groupID))
+	resp, err = hc.Handle(context.Background(), ">")
+	require.NoError(t, err)
+	require.Equal(t, ">yx", resp)
+}
+
+func TestGroupTooBig(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionMaxHandlers(2))
+	hc.AddAnonymousHandler(nil)
+	group := mutableware.NewGroup[string, string]().
+		AddAnonymousHandler(nil).
+		AddAnonymousHandler(nil)
+
+	require.Equal(t, mutableware.GroupID(0), hc.AddGroup(group))
+	require.Len(t, hc.Explain(context.Background(), "req"), 1)
+}
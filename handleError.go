@@ -0,0 +1,72 @@
+package mutableware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// HandleError is the error returned when a handler fails.
+// Use errors.As to retrieve it. errors.Is(err, ErrHandle) is true for
+// every HandleError.
+type HandleError struct {
+	// Info identifies the handler that returned the error.
+	Info HandlerInfo
+	// Err is the error the handler returned.
+	Err error
+	// RequestType is the Request type of the container the handler was in.
+	RequestType reflect.Type
+	// Path is the stack of handlers the request passed through to reach
+	// the failing handler, which is last.
+	Path []HandlerInfo
+	// RequestID is the ID of the request that failed, if it has one.
+	// See ContextWithRequestID.
+	RequestID string
+}
+
+func (e *HandleError) Error() string {
+	return fmt.Sprintf("%s handler=%s%s %s", ErrHandle, e.Info, e.requestIDField(), e.Err)
+}
+
+func (e *HandleError) requestIDField() string {
+	if e.RequestID == "" {
+		return ""
+	}
+	return " requestID=" + e.RequestID
+}
+
+// Format implements fmt.Formatter. The %+v verb includes the Path.
+func (e *HandleError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		path := make([]string, 0, len(e.Path))
+		for _, info := range e.Path {
+			path = append(path, info.String())
+		}
+		fmt.Fprintf(s, "%s handler=%s%s path=%s %+v", ErrHandle, e.Info, e.requestIDField(), strings.Join(path, ">"), e.Err)
+		return
+	}
+	_, _ = io.WriteString(s, e.Error())
+}
+
+func (e *HandleError) Unwrap() error {
+	return e.Err
+}
+
+func (e *HandleError) Is(target error) bool {
+	return target == ErrHandle
+}
+
+// wrapHandleError wraps an error returned by a handler. ctx must be the
+// context that was passed to the handler.
+func wrapHandleError[Request any](ctx context.Context, info HandlerInfo, err error) error {
+	requestID, _ := GetRequestIDFromContext(ctx)
+	return &HandleError{
+		Info:        info,
+		Err:         err,
+		RequestType: reflect.TypeOf((*Request)(nil)).Elem(),
+		Path:        GetHandlerInfoFromContext(ctx),
+		RequestID:   requestID,
+	}
+}
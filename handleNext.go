@@ -0,0 +1,49 @@
+package mutableware
+
+import "context"
+
+// callNext is the function a container falls through to for a single
+// HandleNext call. Containers can be nested, so each one finds its own
+// entry by walking up through the parents.
+type callNext struct {
+	owner  any
+	next   any
+	parent *callNext
+}
+
+func hasCallNext(ctx context.Context) bool {
+	return ctx.Value(callNextCtxKey) != nil
+}
+
+// contextWithCallNext makes owner fall through to next.
+// A nil next makes owner fall through to its terminal, which hides any
+// entry for owner further up, like when a container handles a request from
+// inside its own chain.
+func contextWithCallNext[Request any, Response any](parent context.Context, owner *HandlerContainer[Request, Response], next CurriedHandlerFunc[Request, Response]) context.Context {
+	entry := &callNext{owner: owner}
+	if next != nil {
+		entry.next = next
+	}
+	entry.parent, _ = (parent.Value(callNextCtxKey)).(*callNext)
+	return context.WithValue(parent, callNextCtxKey, entry)
+}
+
+// fallThrough is called when a request reaches the end of the chain.
+func (hc *HandlerContainer[Request, Response]) fallThrough(ctx context.Context, request Request) (Response, error) {
+	var next CurriedHandlerFunc[Request, Response]
+	entry, _ := (ctx.Value(callNextCtxKey)).(*callNext)
+	for ; entry != nil; entry = entry.parent {
+		if entry.owner == any(hc) {
+			next, _ = entry.next.(CurriedHandlerFunc[Request, Response])
+			break
+		}
+	}
+
+	if hc.parent != nil {
+		return hc.parent.HandleNext(ctx, request, next)
+	}
+	if next != nil {
+		return next(ctx, request)
+	}
+	return hc.terminal(ctx, request)
+}
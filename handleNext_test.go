@@ -0,0 +1,57 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleNext(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			resp, err := next(ctx, request+">a")
+			return resp + "<a", err
+		})
+
+	resp, err := hc.HandleNext(context.Background(), "req", func(ctx context.Context, request string) (string, error) {
+		return request + "|end", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "req>a|end<a", resp)
+
+	resp, err = hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "<a", resp)
+}
+
+func TestHandleNextNested(t *testing.T) {
+	inner := mutableware.NewHandlerContainer[string, string]()
+	inner.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return next(ctx, request+">inner")
+		})
+
+	outer := mutableware.NewHandlerContainer[string, string]()
+	outer.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			// inner falls through to the rest of outer's chain.
+			return inner.HandleNext(ctx, request, next)
+		})
+	outer.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			// a plain Handle on inner falls through to its terminal.
+			resp, err := inner.Handle(ctx, request)
+			require.NoError(t, err)
+			require.Equal(t, "", resp)
+			return next(ctx, request+">outer")
+		})
+
+	resp, err := outer.HandleNext(context.Background(), "req", func(ctx context.Context, request string) (string, error) {
+		return request + "|end", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "req>outer>inner|end", resp)
+}
@@ -0,0 +1,98 @@
+package mutableware
+
+import (
+	"context"
+	"time"
+)
+
+type builtHandleOptions struct {
+	skip     []string
+	timeout  time.Duration
+	deadline time.Time
+	values   []handleValue
+	snapshot bool
+}
+
+type handleValue struct {
+	key   any
+	value any
+}
+
+// HandleOption is an option for the HandleWithOptions(...) function.
+type HandleOption func(*builtHandleOptions)
+
+// HandleOptionSkip skips the handlers in the chain that have one of the
+// names, for this request only. See WithHandlerSkipped.
+func HandleOptionSkip(names ...string) HandleOption {
+	return func(o *builtHandleOptions) {
+		o.skip = append(o.skip, names...)
+	}
+}
+
+// HandleOptionTimeout gives the request a deadline d from now, measured by
+// the container's Clock.
+func HandleOptionTimeout(d time.Duration) HandleOption {
+	return func(o *builtHandleOptions) {
+		o.timeout = d
+	}
+}
+
+// HandleOptionDeadline gives the request a deadline, measured by the
+// container's Clock.
+func HandleOptionDeadline(deadline time.Time) HandleOption {
+	return func(o *builtHandleOptions) {
+		o.deadline = deadline
+	}
+}
+
+// HandleOptionValue attaches metadata to the request's context, as if by
+// context.WithValue.
+func HandleOptionValue(key any, value any) HandleOption {
+	return func(o *builtHandleOptions) {
+		o.values = append(o.values, handleValue{key: key, value: value})
+	}
+}
+
+// HandleOptionSnapshot sends the request through the chain as it is when
+// the request starts, even if the container uses ContainerOptionLiveChain.
+func HandleOptionSnapshot() HandleOption {
+	return func(o *builtHandleOptions) {
+		o.snapshot = true
+	}
+}
+
+func buildHandleOptions(opts []HandleOption) *builtHandleOptions {
+	built := &builtHandleOptions{}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+// HandleWithOptions is like Handle, but the options change how this one
+// request is handled. The container itself isn't changed.
+func (hc *HandlerContainer[Request, Response]) HandleWithOptions(ctx context.Context, request Request, options ...HandleOption) (Response, error) {
+	handleOpts := buildHandleOptions(options)
+
+	for _, kv := range handleOpts.values {
+		ctx = context.WithValue(ctx, kv.key, kv.value)
+	}
+	if handleOpts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = withTimeoutCause(ctx, hc.opts.clock, handleOpts.timeout, nil)
+		defer cancel()
+	}
+	if !handleOpts.deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = withDeadlineCause(ctx, hc.opts.clock, handleOpts.deadline, nil)
+		defer cancel()
+	}
+	if len(handleOpts.skip) > 0 || (handleOpts.snapshot && hc.opts.liveChain) {
+		ctx = hc.withOverrides(ctx, func(o *handlerOverrides[Request, Response]) {
+			for _, name := range handleOpts.skip {
+				o.skipNames[name] = struct{}{}
+			}
+		})
+	}
+	return hc.Handle(ctx, request)
+}
@@ -0,0 +1,88 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func echo(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+	return request, nil
+}
+
+func TestHandleOptionSkip(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(echo)
+	hc.AddAnonymousHandler(appendHandler("a"), mutableware.AddOptionName("a"))
+	hc.AddAnonymousHandler(appendHandler("b"), mutableware.AddOptionName("b"))
+	hc.AddAnonymousHandler(appendHandler("c"), mutableware.AddOptionName("c"))
+
+	response, err := hc.HandleWithOptions(context.Background(), "", mutableware.HandleOptionSkip("a", "c"))
+	require.NoError(t, err)
+	require.Equal(t, "b", response)
+
+	response, err = hc.HandleWithOptions(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, "cba", response)
+}
+
+func TestHandleOptionTimeout(t *testing.T) {
+	clock := mutablewaretest.NewFakeClock(epoch)
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionClock(clock))
+	hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		deadline, _ := ctx.Deadline()
+		return deadline.Format(time.RFC3339), nil
+	})
+
+	response, err := hc.HandleWithOptions(context.Background(), "", mutableware.HandleOptionTimeout(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, "2020-01-01T01:00:00Z", response)
+
+	response, err = hc.HandleWithOptions(context.Background(), "",
+		mutableware.HandleOptionTimeout(time.Hour),
+		mutableware.HandleOptionDeadline(epoch.Add(time.Minute)))
+	require.NoError(t, err)
+	require.Equal(t, "2020-01-01T00:01:00Z", response)
+	require.Equal(t, 0, clock.Timers())
+}
+
+type metadataKey struct{}
+
+func TestHandleOptionValue(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		value, _ := ctx.Value(metadataKey{}).(string)
+		return value, nil
+	})
+
+	response, err := hc.HandleWithOptions(context.Background(), "", mutableware.HandleOptionValue(metadataKey{}, "meta"))
+	require.NoError(t, err)
+	require.Equal(t, "meta", response)
+}
+
+func TestHandleOptionSnapshot(t *testing.T) {
+	// build a container whose first request swaps out the handler after it.
+	build := func() *mutableware.HandlerContainer[string, string] {
+		hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionLiveChain())
+		echoID := hc.AddAnonymousHandler(echo)
+		hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+				return "swapped", nil
+			}, mutableware.AddOptionSwap(echoID))
+			return next(ctx, request)
+		})
+		return hc
+	}
+
+	response, err := build().HandleWithOptions(context.Background(), "original", mutableware.HandleOptionSnapshot())
+	require.NoError(t, err)
+	require.Equal(t, "original", response)
+
+	response, err = build().HandleWithOptions(context.Background(), "original")
+	require.NoError(t, err)
+	require.Equal(t, "swapped", response)
+}
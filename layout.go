@@ -0,0 +1,43 @@
+package mutableware
+
+// Layout describes the handlers in a container.
+// Each list is in the order its handlers are invoked.
+type Layout struct {
+	// Chain holds the handlers that every request is sent through,
+	// starting with those added from a Registry and ending with the one
+	// from ContainerOptionTerminalHandler.
+	Chain []HandlerInfo
+	// Fallbacks holds the handlers added with AddOptionFallback.
+	Fallbacks []HandlerInfo
+	// FastPaths holds the handlers added with AddOptionFastPath. Only the
+	// first one is used.
+	FastPaths []HandlerInfo
+}
+
+// Layout returns the handlers in the container as they are right now.
+func (hc *HandlerContainer[Request, Response]) Layout() Layout {
+	hc.mux.RLock()
+	defer hc.mux.RUnlock()
+
+	layout := Layout{
+		Chain:     make([]HandlerInfo, 0, len(hc.stack)+len(hc.pinned)+len(hc.bottom)),
+		Fallbacks: make([]HandlerInfo, 0, len(hc.fallbacks)),
+		FastPaths: make([]HandlerInfo, 0, len(hc.fastPaths)),
+	}
+	for i := len(hc.pinned) - 1; i >= 0; i-- {
+		layout.Chain = append(layout.Chain, hc.pinned[i].info)
+	}
+	for i := len(hc.stack) - 1; i >= 0; i-- {
+		layout.Chain = append(layout.Chain, hc.stack[i].info)
+	}
+	for _, handler := range hc.bottom {
+		layout.Chain = append(layout.Chain, handler.info)
+	}
+	for i := len(hc.fallbacks) - 1; i >= 0; i-- {
+		layout.Fallbacks = append(layout.Fallbacks, hc.fallbacks[i].info)
+	}
+	for i := len(hc.fastPaths) - 1; i >= 0; i-- {
+		layout.FastPaths = append(layout.FastPaths, hc.fastPaths[i].info)
+	}
+	return layout
+}
@@ -0,0 +1,40 @@
+package mutableware_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayout(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	require.Equal(t, mutableware.Layout{
+		Chain:     []mutableware.HandlerInfo{},
+		Fallbacks: []mutableware.HandlerInfo{},
+		FastPaths: []mutableware.HandlerInfo{},
+	}, hc.Layout())
+
+	firstID := hc.AddAnonymousHandler(nil, mutableware.AddOptionName("first"))
+	secondID := hc.AddAnonymousHandler(nil, mutableware.AddOptionName("second"))
+	lastID := hc.AddAnonymousHandler(nil, mutableware.AddOptionName("last"), mutableware.AddOptionLast())
+	fallbackID := hc.AddAnonymousHandler(nil, mutableware.AddOptionName("fallback"), mutableware.AddOptionFallback())
+	fastID := hc.AddAnonymousHandler(nil, mutableware.AddOptionName("fast"), mutableware.AddOptionFastPath(time.Second))
+
+	require.Equal(t, mutableware.Layout{
+		Chain: []mutableware.HandlerInfo{
+			{ID: secondID, Name: "second"},
+			{ID: firstID, Name: "first"},
+			{ID: lastID, Name: "last"},
+		},
+		Fallbacks: []mutableware.HandlerInfo{{ID: fallbackID, Name: "fallback"}},
+		FastPaths: []mutableware.HandlerInfo{{ID: fastID, Name: "fast"}},
+	}, hc.Layout())
+
+	hc.Remove(firstID)
+	require.Equal(t, []mutableware.HandlerInfo{
+		{ID: secondID, Name: "second"},
+		{ID: lastID, Name: "last"},
+	}, hc.Layout().Chain)
+}
@@ -0,0 +1,67 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+)
+
+// Initializer is an optional interface for Handlers.
+// Init is called each time the handler is added to a container, before any
+// requests are sent to it. If it returns an error, the handler isn't added.
+// The container is locked while Init runs, so Init must not call it.
+type Initializer interface {
+	Init() error
+}
+
+// initHandler runs the handler's Init function, if it has one.
+func initHandler(handler any) error {
+	if initializer, ok := handler.(Initializer); ok {
+		return initializer.Init()
+	}
+	return nil
+}
+
+// closerOf returns the handler's Close function, if it has one.
+func closerOf(handler any) Closer {
+	closer, _ := handler.(Closer)
+	return closer
+}
+
+// closers closes several handlers that share a place in the chain.
+type closers []Closer
+
+func (c closers) Close(ctx context.Context) error {
+	var errs []error
+	for _, closer := range c {
+		if closer != nil {
+			errs = append(errs, closer.Close(ctx))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// retire records that a handler has left the container, so that it's closed
+// once the lock is released. The lock must be held.
+func (hc *HandlerContainer[Request, Response]) retire(handler identifiedHandler[Request, Response]) {
+	if handler.closer != nil && !hc.tornDown {
+		hc.retired = append(hc.retired, handler.closer)
+	}
+}
+
+// unlock releases the lock, and then closes the handlers that were retired
+// while it was held. Errors from closing them are dropped, since there's no
+// caller to return them to. Issues found by ContainerOptionDebug are
+// reported last.
+func (hc *HandlerContainer[Request, Response]) unlock() {
+	retired := hc.retired
+	hc.retired = nil
+	issues := hc.takeIssues()
+	hc.mux.Unlock()
+
+	for i := len(retired) - 1; i >= 0; i-- {
+		_ = retired[i].Close(context.Background())
+	}
+	if len(issues) > 0 {
+		hc.report(issues)
+	}
+}
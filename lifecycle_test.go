@@ -0,0 +1,71 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+type lifecycleHandler struct {
+	initErr error
+	inits   int
+	closes  int
+}
+
+func (h *lifecycleHandler) Init() error {
+	h.inits++
+	return h.initErr
+}
+
+func (h *lifecycleHandler) Close(ctx context.Context) error {
+	h.closes++
+	return nil
+}
+
+func (h *lifecycleHandler) Handle(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+	return next(ctx, request)
+}
+
+func TestLifecycle(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+
+	removed := &lifecycleHandler{}
+	id := hc.Add(removed, mutableware.AddOptionTimeout(time.Hour))
+	require.Equal(t, 1, removed.inits)
+	hc.Remove(id)
+	require.Equal(t, 1, removed.closes)
+
+	swapped := &lifecycleHandler{}
+	replacement := &lifecycleHandler{}
+	id = hc.Add(swapped)
+	hc.Add(replacement, mutableware.AddOptionSwap(id))
+	require.Equal(t, 1, swapped.closes)
+	require.Equal(t, 0, replacement.closes)
+
+	failed := &lifecycleHandler{initErr: errors.New("no connection")}
+	_, err := hc.TryAdd(failed)
+	require.ErrorIs(t, err, failed.initErr)
+	require.Equal(t, mutableware.HandlerID(0), hc.Add(failed))
+	require.Equal(t, 0, failed.closes)
+
+	require.NoError(t, hc.Close(context.Background()))
+	require.Equal(t, 1, replacement.closes)
+	require.Equal(t, 1, swapped.closes)
+	require.Equal(t, 1, removed.closes)
+}
+
+func TestLifecycleGroup(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	first := &lifecycleHandler{}
+	failed := &lifecycleHandler{initErr: errors.New("no connection")}
+	group := mutableware.NewGroup[string, string]().Add(first).Add(failed)
+
+	require.Equal(t, mutableware.GroupID(0), hc.AddGroup(group))
+	require.Equal(t, 1, first.inits)
+	require.Equal(t, 1, first.closes)
+	require.Empty(t, hc.Explain(context.Background(), "req"))
+}
@@ -0,0 +1,43 @@
+package mutableware
+
+import (
+	"context"
+	"slices"
+)
+
+// liveNext returns a function that invokes the handler that currently
+// follows prevID in the chain. prevIdx is where prevID was last seen,
+// which is used to resume the chain if prevID has since been removed.
+// Pass an index of -1 to start from the top of the chain.
+func (hc *HandlerContainer[Request, Response]) liveNext(prevID HandlerID, prevIdx int) CurriedHandlerFunc[Request, Response] {
+	return func(ctx context.Context, request Request) (Response, error) {
+		hc.mux.RLock()
+		idx := len(hc.stack) - 1
+		if prevIdx >= 0 {
+			idx = min(prevIdx, len(hc.stack)) - 1
+			if found := slices.IndexFunc(hc.stack, func(e identifiedHandler[Request, Response]) bool {
+				return e.info.ID == prevID
+			}); found >= 0 {
+				idx = found - 1
+			}
+		}
+		if idx < 0 {
+			hc.mux.RUnlock()
+			if len(hc.bottom) > 0 {
+				return hc.invoke(ctx, request, hc.bottom[0], hc.fallThrough)
+			}
+			return hc.fallThrough(ctx, request)
+		}
+		handler := hc.stack[idx]
+		for _, downstream := range hc.stack[:idx] {
+			handler.downstreamWeight += downstream.weight
+		}
+		fastPath, hasFastPath := hc.currentFastPath()
+		hc.mux.RUnlock()
+
+		if hasFastPath && fastPath.due(ctx, hc.opts.clock) {
+			return hc.invoke(ctx, request, fastPath.identifiedHandler, hc.fallThrough)
+		}
+		return hc.invoke(ctx, request, handler, hc.liveNext(handler.info.ID, idx))
+	}
+}
@@ -0,0 +1,41 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiveChain(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, []string](mutableware.ContainerOptionLiveChain())
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, []string]) ([]string, error) {
+			out, err := next(ctx, request)
+			return append(out, "base"), err
+		})
+	removeID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, []string]) ([]string, error) {
+			out, err := next(ctx, request)
+			return append(out, "removed"), err
+		})
+	selfID := mutableware.HandlerID(0)
+	selfID = hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, []string]) ([]string, error) {
+			// add a follow-up handler beneath this one and remove another.
+			hc.Remove(removeID)
+			hc.AddAnonymousHandler(
+				func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, []string]) ([]string, error) {
+					out, err := next(ctx, request)
+					return append(out, "follow-up"), err
+				}, mutableware.AddOptionLast())
+			hc.Remove(selfID)
+			out, err := next(ctx, request)
+			return append(out, "self"), err
+		})
+
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, []string{"follow-up", "base", "self"}, resp)
+}
@@ -0,0 +1,100 @@
+package mutableware
+
+import (
+	"context"
+	"log/slog"
+)
+
+// logAdd logs the result of add. The lock must be held.
+func (hc *HandlerContainer[Request, Response]) logAdd(id HandlerID, replaced HandlerID, addOpts *builtAddOptions, err error) {
+	attrs := []slog.Attr{
+		slog.String("container", hc.opts.name),
+		slog.String("name", addOpts.name),
+		slog.Uint64("id", uint64(id)),
+		slog.Group("options", addOpts.logAttrs()...),
+		slog.Int("handlers", hc.size()),
+	}
+	switch {
+	case err != nil:
+		attrs = append(attrs, slog.Any("error", err))
+		hc.opts.logger.LogAttrs(context.Background(), slog.LevelWarn, "mutableware: handler not added", attrs...)
+	case replaced != 0:
+		attrs = append(attrs, slog.Uint64("replaced", uint64(replaced)))
+		hc.opts.logger.LogAttrs(context.Background(), slog.LevelInfo, "mutableware: handler swapped", attrs...)
+	default:
+		hc.opts.logger.LogAttrs(context.Background(), slog.LevelInfo, "mutableware: handler added", attrs...)
+	}
+}
+
+// logRemove logs a handler that remove took out of the container. The lock
+// must be held.
+func (hc *HandlerContainer[Request, Response]) logRemove(info HandlerInfo) {
+	hc.opts.logger.LogAttrs(context.Background(), slog.LevelInfo, "mutableware: handler removed",
+		slog.String("container", hc.opts.name),
+		slog.String("name", info.Name),
+		slog.Uint64("id", uint64(info.ID)),
+		slog.Int("handlers", hc.size()),
+	)
+}
+
+// logMerge logs a handler that Merge copied from another container, once
+// all of them have been added. The lock must be held.
+func (hc *HandlerContainer[Request, Response]) logMerge(info HandlerInfo, from HandlerID) {
+	hc.opts.logger.LogAttrs(context.Background(), slog.LevelInfo, "mutableware: handler merged",
+		slog.String("container", hc.opts.name),
+		slog.String("name", info.Name),
+		slog.Uint64("id", uint64(info.ID)),
+		slog.Uint64("from", uint64(from)),
+		slog.Int("handlers", hc.size()),
+	)
+}
+
+// logMergeFailed logs a Merge that was refused because hc didn't have room
+// for count more handlers. The lock must be held.
+func (hc *HandlerContainer[Request, Response]) logMergeFailed(count int) {
+	hc.opts.logger.LogAttrs(context.Background(), slog.LevelWarn, "mutableware: handlers not merged",
+		slog.String("container", hc.opts.name),
+		slog.Int("count", count),
+		slog.Int("handlers", hc.size()),
+		slog.Any("error", ErrTooManyHandlers),
+	)
+}
+
+// logAttrs describes the options that were set.
+func (o *builtAddOptions) logAttrs() []any {
+	var attrs []any
+	if o.last {
+		attrs = append(attrs, slog.Bool("last", true))
+	}
+	if o.fallback {
+		attrs = append(attrs, slog.Bool("fallback", true))
+	}
+	if o.shadow {
+		attrs = append(attrs, slog.Bool("shadow", true))
+	}
+	if o.swapID != 0 {
+		attrs = append(attrs, slog.Uint64("swap", uint64(o.swapID)))
+	}
+	if o.canaryID != 0 {
+		attrs = append(attrs, slog.Uint64("canary", uint64(o.canaryID)), slog.Float64("canaryPercent", o.canaryPercent))
+	}
+	if o.retryAttempts > 0 {
+		attrs = append(attrs, slog.Int("retryAttempts", o.retryAttempts))
+	}
+	if o.fastPath {
+		attrs = append(attrs, slog.Duration("fastPath", o.fastPathThreshold))
+	}
+	if o.mapError != nil {
+		attrs = append(attrs, slog.Bool("mapError", true))
+	}
+	if o.deadlineWeight > 0 {
+		attrs = append(attrs, slog.Float64("deadlineWeight", o.deadlineWeight))
+	}
+	if o.timeout > 0 {
+		attrs = append(attrs, slog.Duration("timeout", o.timeout))
+	}
+	if o.maxConcurrency > 0 {
+		attrs = append(attrs, slog.Int("maxConcurrency", o.maxConcurrency), slog.Int("concurrencyQueue", o.concurrencyQueue))
+	}
+	return attrs
+}
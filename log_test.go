@@ -0,0 +1,101 @@
+package mutableware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionName("main"),
+		mutableware.ContainerOptionLogger(logger),
+		mutableware.ContainerOptionMaxHandlers(2))
+
+	firstID := hc.AddAnonymousHandler(nil, mutableware.AddOptionName("first"), mutableware.AddOptionLast())
+	secondID := hc.AddAnonymousHandler(nil, mutableware.AddOptionName("second"), mutableware.AddOptionSwap(firstID))
+	hc.AddAnonymousHandler(nil, mutableware.AddOptionName("third"))
+	_, err := hc.TryAdd(mutableware.HandlerFunc[string, string](nil).Handler(), mutableware.AddOptionName("fourth"))
+	require.ErrorIs(t, err, mutableware.ErrTooManyHandlers)
+	hc.Remove(secondID)
+
+	var lines []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		line := map[string]any{}
+		require.NoError(t, dec.Decode(&line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 5)
+
+	require.Equal(t, "mutableware: handler added", lines[0]["msg"])
+	require.Equal(t, "main", lines[0]["container"])
+	require.Equal(t, "first", lines[0]["name"])
+	require.EqualValues(t, firstID, lines[0]["id"])
+	require.Equal(t, map[string]any{"last": true}, lines[0]["options"])
+	require.EqualValues(t, 1, lines[0]["handlers"])
+
+	require.Equal(t, "mutableware: handler swapped", lines[1]["msg"])
+	require.EqualValues(t, secondID, lines[1]["id"])
+	require.EqualValues(t, firstID, lines[1]["replaced"])
+	require.EqualValues(t, 1, lines[1]["handlers"])
+
+	require.Equal(t, "mutableware: handler added", lines[2]["msg"])
+	require.EqualValues(t, 2, lines[2]["handlers"])
+
+	require.Equal(t, "WARN", lines[3]["level"])
+	require.Equal(t, "fourth", lines[3]["name"])
+	require.Contains(t, lines[3], "error")
+
+	require.Equal(t, "mutableware: handler removed", lines[4]["msg"])
+	require.Equal(t, "second", lines[4]["name"])
+	require.EqualValues(t, 1, lines[4]["handlers"])
+}
+
+func TestLoggerMerge(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionName("main"),
+		mutableware.ContainerOptionLogger(logger),
+		mutableware.ContainerOptionMaxHandlers(3))
+	hc.AddAnonymousHandler(nil, mutableware.AddOptionName("existing"))
+	buf.Reset()
+
+	other := mutableware.NewHandlerContainer[string, string]()
+	otherID := other.AddAnonymousHandler(nil, mutableware.AddOptionName("merged"))
+	other.AddAnonymousHandler(nil, mutableware.AddOptionFallback())
+	ids := hc.Merge(other)
+	require.Len(t, ids, 2)
+	require.Nil(t, hc.Merge(other))
+
+	var lines []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		line := map[string]any{}
+		require.NoError(t, dec.Decode(&line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 3)
+
+	require.Equal(t, "mutableware: handler merged", lines[0]["msg"])
+	require.Equal(t, "main", lines[0]["container"])
+	require.Equal(t, "merged", lines[0]["name"])
+	require.EqualValues(t, ids[otherID], lines[0]["id"])
+	require.EqualValues(t, otherID, lines[0]["from"])
+	require.EqualValues(t, 3, lines[0]["handlers"])
+
+	require.Equal(t, "mutableware: handler merged", lines[1]["msg"])
+	require.Equal(t, "", lines[1]["name"])
+
+	require.Equal(t, "WARN", lines[2]["level"])
+	require.Equal(t, "mutableware: handlers not merged", lines[2]["msg"])
+	require.EqualValues(t, 2, lines[2]["count"])
+	require.Contains(t, lines[2], "error")
+}
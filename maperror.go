@@ -0,0 +1,21 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+)
+
+// mapErrorHandler transforms the errors a handler returns.
+type mapErrorHandler[Request any, Response any] struct {
+	Handler[Request, Response]
+	mapError func(error) error
+}
+
+func (m *mapErrorHandler[Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	tracker := &nextTracker{}
+	out, err := m.Handler.Handle(ctx, request, trackNext(tracker, next))
+	if err == nil || errors.Is(err, ErrStop) || tracker.returned(err) {
+		return out, err
+	}
+	return out, m.mapError(err)
+}
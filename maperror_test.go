@@ -0,0 +1,47 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapError(t *testing.T) {
+	thirdPartyErr := errors.New("third_party")
+	domainErr := errors.New("domain")
+	downstreamErr := errors.New("downstream")
+
+	failDownstream := false
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			if failDownstream {
+				return "", downstreamErr
+			}
+			return "", nil
+		})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			if _, err := next(ctx, request); err != nil {
+				return "", err
+			}
+			return "", thirdPartyErr
+		},
+		mutableware.AddOptionMapError(func(err error) error {
+			return fmt.Errorf("%w: %w", domainErr, err)
+		}))
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, domainErr)
+	require.ErrorIs(t, err, thirdPartyErr)
+
+	// errors from other handlers are left alone
+	failDownstream = true
+	_, err = hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, downstreamErr)
+	require.NotErrorIs(t, err, domainErr)
+}
@@ -0,0 +1,134 @@
+package mutableware
+
+import "slices"
+
+type builtMergeOptions struct {
+	last       bool
+	namePrefix string
+}
+
+// MergeOption is an option for the Merge(...) function.
+type MergeOption func(*builtMergeOptions)
+
+// MergeOptionLast puts the merged handlers after the existing handlers in
+// the chain, instead of before them.
+func MergeOptionLast() MergeOption {
+	return func(o *builtMergeOptions) {
+		o.last = true
+	}
+}
+
+// MergeOptionNamePrefix prepends prefix to the names of the merged handlers.
+func MergeOptionNamePrefix(prefix string) MergeOption {
+	return func(o *builtMergeOptions) {
+		o.namePrefix = prefix
+	}
+}
+
+func buildMergeOptions(opts []MergeOption) *builtMergeOptions {
+	built := &builtMergeOptions{}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+// Merge adds all of the handlers in other to this container, as if they had
+// been added one at a time in the same order. Fallback and fast-path
+// handlers are merged too. The merged handlers get new IDs in this
+// container; the returned map goes from their IDs in other to their new IDs.
+// other isn't changed.
+//
+// The handlers are added all at once, so requests never see a partial merge.
+// If hc doesn't have room for all of them (see ContainerOptionMaxHandlers),
+// none are added and nil is returned. With ContainerOptionLogger, each merged
+// handler is logged once the merge is done, as is a refused merge.
+func (hc *HandlerContainer[Request, Response]) Merge(other *HandlerContainer[Request, Response], options ...MergeOption) map[HandlerID]HandlerID {
+	mergeOpts := buildMergeOptions(options)
+
+	other.mux.RLock()
+	stack := slices.Clone(other.stack)
+	fallbacks := slices.Clone(other.fallbacks)
+	fastPaths := slices.Clone(other.fastPaths)
+	other.mux.RUnlock()
+
+	hc.lock()
+	defer hc.unlock()
+	defer hc.invalidate()
+
+	if count := len(stack) + len(fallbacks) + len(fastPaths); !hc.hasRoomFor(count) {
+		if hc.opts.logger != nil {
+			hc.logMergeFailed(count)
+		}
+		return nil
+	}
+
+	ids := make(map[HandlerID]HandlerID, len(stack)+len(fallbacks)+len(fastPaths))
+	var merged []HandlerInfo
+	var from []HandlerID
+	remap := func(handler identifiedHandler[Request, Response]) identifiedHandler[Request, Response] {
+		id := HandlerID(hc.nextID)
+		hc.nextID = hc.nextID + 1
+		ids[handler.info.ID] = id
+		from = append(from, handler.info.ID)
+
+		handler.info.ID = id
+		// other still owns the handler, so it's the one to close it.
+		handler.closer = nil
+		if handler.info.Name != "" || mergeOpts.namePrefix != "" {
+			handler.info.Name = mergeOpts.namePrefix + handler.info.Name
+		}
+		handler.Handler = withInfo(handler.Handler, handler.info)
+		merged = append(merged, handler.info)
+		return handler
+	}
+
+	for i := range stack {
+		stack[i] = remap(stack[i])
+	}
+	for i := range fallbacks {
+		fallbacks[i] = remap(fallbacks[i])
+	}
+	for i := range fastPaths {
+		fastPaths[i].identifiedHandler = remap(fastPaths[i].identifiedHandler)
+	}
+
+	if mergeOpts.last {
+		hc.stack = append(stack, hc.stack...)
+	} else {
+		hc.stack = append(hc.stack, stack...)
+	}
+	hc.fallbacks = append(hc.fallbacks, fallbacks...)
+	hc.fastPaths = append(hc.fastPaths, fastPaths...)
+	if hc.opts.logger != nil {
+		for i, info := range merged {
+			hc.logMerge(info, from[i])
+		}
+	}
+	return ids
+}
+
+// withInfo returns a copy of handler whose wrappers from AddOptions report
+// info instead of the HandlerInfo they were made with.
+func withInfo[Request any, Response any](handler Handler[Request, Response], info HandlerInfo) Handler[Request, Response] {
+	switch h := handler.(type) {
+	case *shadowHandler[Request, Response]:
+		remapped := *h
+		remapped.Handler = withInfo(h.Handler, info)
+		remapped.info = info
+		return &remapped
+	case *retryHandler[Request, Response]:
+		remapped := *h
+		remapped.Handler = withInfo(h.Handler, info)
+		return &remapped
+	case *concurrencyHandler[Request, Response]:
+		remapped := *h
+		remapped.Handler = withInfo(h.Handler, info)
+		return &remapped
+	case *timeoutHandler[Request, Response]:
+		remapped := *h
+		remapped.info = info
+		return &remapped
+	}
+	return handler
+}
@@ -0,0 +1,77 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	newBase := func() *mutableware.HandlerContainer[string, string] {
+		hc := mutableware.NewHandlerContainer[string, string]()
+		hc.AddAnonymousHandler(
+			func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+				return request, nil
+			})
+		hc.AddAnonymousHandler(appendHandler("x"))
+		return hc
+	}
+
+	plugin := mutableware.NewHandlerContainer[string, string]()
+	aID := plugin.AddAnonymousHandler(appendHandler("a"), mutableware.AddOptionName("a"))
+	plugin.AddAnonymousHandler(appendHandler("b"))
+
+	hc := newBase()
+	ids := hc.Merge(plugin, mutableware.MergeOptionNamePrefix("plugin."))
+	require.Len(t, ids, 2)
+	resp, err := hc.Handle(context.Background(), ">")
+	require.NoError(t, err)
+	require.Equal(t, ">bax", resp)
+
+	explanations := hc.Explain(context.Background(), ">")
+	require.Equal(t, "plugin.a", explanations[1].Info.Name)
+	require.Equal(t, ids[aID], explanations[1].Info.ID)
+
+	hc.Remove(ids[aID])
+	resp, err = hc.Handle(context.Background(), ">")
+	require.NoError(t, err)
+	require.Equal(t, ">bx", resp)
+
+	// the merged container isn't changed.
+	require.Len(t, plugin.Explain(context.Background(), ">"), 2)
+
+	last := mutableware.NewHandlerContainer[string, string]()
+	last.AddAnonymousHandler(appendHandler("x"))
+	last.Merge(plugin, mutableware.MergeOptionLast())
+	resp, err = last.HandleNext(context.Background(), ">", func(ctx context.Context, request string) (string, error) {
+		return request, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, ">xba", resp)
+}
+
+func TestMergeTimeout(t *testing.T) {
+	plugin := mutableware.NewHandlerContainer[string, string]()
+	plugin.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+		mutableware.AddOptionName("slow"),
+		mutableware.AddOptionTimeout(time.Millisecond),
+		mutableware.AddOptionRetry(2, nil))
+
+	hc := mutableware.NewHandlerContainer[string, string]()
+	ids := hc.Merge(plugin, mutableware.MergeOptionNamePrefix("plugin."))
+	require.Len(t, ids, 1)
+
+	_, err := hc.Handle(context.Background(), ">")
+	var timeoutErr *mutableware.TimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	for _, id := range ids {
+		require.Equal(t, mutableware.HandlerInfo{ID: id, Name: "plugin.slow"}, timeoutErr.Info)
+	}
+}
@@ -6,18 +6,40 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"reflect"
 	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // ErrHandle is returned when one or more handlers return an
 // error in their Handle(...) calls.
 var ErrHandle = errors.New("handleError")
 
+// ErrTooManyHandlers is returned by TryAdd when the container already holds
+// as many handlers as ContainerOptionMaxHandlers allows.
+var ErrTooManyHandlers = errors.New("too many handlers")
+
+// ErrNoHandlers is returned when a request falls through every handler in a
+// container made with ContainerOptionNoHandlersError.
+var ErrNoHandlers = errors.New("noHandlers")
+
+// ErrStop can be returned by a handler to stop the chain without failing
+// the request. It is passed unwrapped to upstream handlers, and Handle
+// replaces it with a nil error.
+var ErrStop = errors.New("stop")
+
 // HandlerID identifies a handler. Use this to remove a handler
 // from a container.
 type HandlerID uint64
 
+// terminalID is the HandlerID of the handler from
+// ContainerOptionTerminalHandler. It's outside the range that added
+// handlers are given.
+const terminalID = HandlerID(math.MaxUint64)
+
 // HandlerContainer is an ordered collection of Handlers of the same type.
 // When a Request is sent to a HandlerContainer, Handlers are invoked in
 // in the reverse order that they were added (the oldest Handler is executed
@@ -25,22 +47,122 @@ type HandlerID uint64
 //
 // Handlers can be removed after being added. This is a distinguishing feature
 // of this package versus traditional middleware packages.
+//
+// A HandlerContainer is safe for concurrent use, and handlers may add or
+// remove handlers from the container they are running in. By default, such
+// changes take effect starting with the next call to Handle.
+// See ContainerOptionLiveChain to make them visible to in-flight requests.
 type HandlerContainer[Request any, Response any] struct {
 	// stack of Handlers. Oldest first.
-	stack         []identifiedHandler[Request, Response]
-	nextID        uint64
-	cachedHandler CurriedHandlerFunc[Request, Response]
-	mux           *sync.RWMutex
+	stack []identifiedHandler[Request, Response]
+	// pinned handlers always run before the stack. Oldest first.
+	pinned []identifiedHandler[Request, Response]
+	// bottom holds the handler from ContainerOptionTerminalHandler, which
+	// runs after the stack. It never changes once the container is built.
+	bottom    []identifiedHandler[Request, Response]
+	fallbacks []identifiedHandler[Request, Response]
+	fastPaths []fastPathHandler[Request, Response]
+	// finalizers run after every request. Oldest first.
+	finalizers []finalizer[Request, Response]
+	nextID     uint64
+	// current is the snapshot that Handle uses. It's replaced, never
+	// modified, so Handle can load it without taking the lock.
+	// It's nil when the container has changed since it was built.
+	current atomic.Pointer[snapshot[Request, Response]]
+	// version counts the changes made to the container.
+	version      uint64
+	lastMutation time.Time
+	// lockedAt is when the lock was taken, for ContainerOptionDebug.
+	lockedAt time.Time
+	// issues found by ContainerOptionDebug while the lock is held.
+	issues []*DebugIssue
+	// terminal is invoked when the chain falls through.
+	terminal CurriedHandlerFunc[Request, Response]
+	// parent is the container whose chain this one falls through to.
+	parent        *HandlerContainer[Request, Response]
+	errorHandlers failureHandler[Request, Response]
+	groups        map[GroupID][]HandlerID
+	// inflight counts requests that are being handled. Once closed is
+	// set and it drops to 0, drained is closed.
+	inflight  atomic.Int64
+	closed    atomic.Bool
+	drained   chan struct{}
+	drainOnce sync.Once
+	tornDown  bool
+	// retired are closed once the lock is released.
+	retired []Closer
+	// limiter caps concurrent requests. It's nil if there is no limit.
+	limiter *limiter
+	// stats is nil unless ContainerOptionStats is set.
+	stats *statsObserver
+	// paused is closed when the container is resumed.
+	// It's nil if the container isn't paused.
+	paused atomic.Pointer[chan struct{}]
+	// mux guards mutations. Handle doesn't take it; see current.
+	mux  *sync.RWMutex
+	opts *builtContainerOptions
 }
 
 // NewHandlerContainer creates a new container for Handlers of the same type.
-func NewHandlerContainer[Request any, Response any]() *HandlerContainer[Request, Response] {
-	return &HandlerContainer[Request, Response]{
-		stack:         []identifiedHandler[Request, Response]{},
-		nextID:        10,
-		cachedHandler: nilCurriedHandlerFunc[Request, Response],
-		mux:           &sync.RWMutex{},
+func NewHandlerContainer[Request any, Response any](options ...ContainerOption) *HandlerContainer[Request, Response] {
+	return newHandlerContainer[Request, Response](buildContainerOptions(options))
+}
+
+func newHandlerContainer[Request any, Response any](opts *builtContainerOptions) *HandlerContainer[Request, Response] {
+	hc := &HandlerContainer[Request, Response]{
+		stack:    []identifiedHandler[Request, Response]{},
+		nextID:   10,
+		terminal: nilCurriedHandlerFunc[Request, Response],
+		drained:  make(chan struct{}),
+		mux:      &sync.RWMutex{},
+		opts:     opts,
+	}
+	if hc.opts.firstID > 0 {
+		hc.nextID = uint64(hc.opts.firstID)
+	}
+	if hc.opts.bottom != nil {
+		handler, ok := hc.opts.bottom.(Handler[Request, Response])
+		if !ok {
+			panic(fmt.Sprintf("mutableware: ContainerOptionTerminalHandler needs a %v, not a %T", reflect.TypeOf(&handler).Elem(), hc.opts.bottom))
+		}
+		hc.bottom = []identifiedHandler[Request, Response]{{
+			Handler: handler,
+			info:    HandlerInfo{ID: terminalID, Name: "terminal"},
+			closer:  closerOf(handler),
+		}}
+	}
+	if hc.opts.defaultResp != nil {
+		respond, ok := hc.opts.defaultResp.(func(context.Context, Request) (Response, error))
+		if !ok {
+			panic(fmt.Sprintf("mutableware: ContainerOptionDefaultResponse needs a %T, not a %T", respond, hc.opts.defaultResp))
+		}
+		hc.terminal = respond
+	} else if hc.opts.noHandlersErr {
+		hc.terminal = noHandlersCurriedHandlerFunc[Request, Response]
+	}
+	if validate, ok := hc.opts.validate.(func(Response) error); hc.opts.validate != nil && !ok {
+		panic(fmt.Sprintf("mutableware: ContainerOptionValidateResponse needs a %T, not a %T", validate, hc.opts.validate))
+	}
+	if hc.opts.registry != nil {
+		hc.applyRegistry()
 	}
+	if hc.opts.maxHandles > 0 {
+		hc.limiter = newLimiter(hc.opts.maxHandles, hc.opts.handlesQueue, hc.opts.clock)
+		hc.limiter.full = ErrQueueFull
+		hc.limiter.queueWait = hc.opts.queueWait
+	}
+	if hc.opts.stats {
+		hc.stats = &statsObserver{}
+	}
+	if hc.opts.expvarName != "" {
+		hc.publishExpvar(hc.opts.expvarName)
+	}
+	return hc
+}
+
+// Name returns the name the container was given with ContainerOptionName.
+func (hc *HandlerContainer[Request, Response]) Name() string {
+	return hc.opts.name
 }
 
 // Add a new handler to the container. Newer handlers are invoked first.
@@ -51,21 +173,111 @@ func (hc *HandlerContainer[Request, Response]) AddAnonymousHandler(handlerFn Han
 
 // Add a new handler to the container. Newer handlers are invoked first.
 // Retain the returned HandlerID if you need to Remove() this handler later.
+// If the container is full (see ContainerOptionMaxHandlers) or the
+// handler's Init function fails (see Initializer), the handler isn't added
+// and 0 is returned.
 func (hc *HandlerContainer[Request, Response]) Add(handler Handler[Request, Response], options ...AddOption) HandlerID {
-	hc.mux.Lock()
-	defer hc.mux.Unlock()
-	defer hc.buildHandlers()
+	id, _ := hc.TryAdd(handler, options...)
+	return id
+}
 
-	id := HandlerID(hc.nextID)
-	hc.nextID = hc.nextID + 1
+// TryAdd is like Add, but returns an error if the handler can't be added:
+// ErrTooManyHandlers if the container is full, or the error from the
+// handler's Init function.
+func (hc *HandlerContainer[Request, Response]) TryAdd(handler Handler[Request, Response], options ...AddOption) (HandlerID, error) {
+	hc.lock()
+	defer hc.unlock()
+	defer hc.invalidate()
+	return hc.add(handler, options)
+}
+
+// add adds a handler without rebuilding the chain. It returns 0 and an
+// error if the handler can't be added. The lock must be held.
+func (hc *HandlerContainer[Request, Response]) add(handler Handler[Request, Response], options []AddOption) (HandlerID, error) {
 	addOpts := buildAddOptions(options)
+	id, replaced, err := hc.insert(handler, addOpts)
+	if hc.opts.logger != nil {
+		hc.logAdd(id, replaced, addOpts, err)
+	}
+	return id, err
+}
+
+// insert adds a handler for add. If it takes the place of another handler,
+// that handler's ID is returned as replaced. The lock must be held.
+func (hc *HandlerContainer[Request, Response]) insert(handler Handler[Request, Response], addOpts *builtAddOptions) (id HandlerID, replaced HandlerID, err error) {
+	observe, ok := addOpts.canaryObserve.(CanaryObserveFunc[Request, Response])
+	if addOpts.canaryObserve != nil && !ok {
+		panic(fmt.Sprintf("mutableware: AddOptionCanaryObserver needs a %T, not a %T", observe, addOpts.canaryObserve))
+	}
+	if !hc.replaces(addOpts) && !hc.hasRoomFor(1) {
+		return HandlerID(0), HandlerID(0), ErrTooManyHandlers
+	}
+	if err := initHandler(handler); err != nil {
+		return HandlerID(0), HandlerID(0), err
+	}
+	closer := closerOf(handler)
+
+	id = HandlerID(hc.nextID)
+	hc.nextID = hc.nextID + 1
+
+	info := HandlerInfo{
+		ID:   id,
+		Name: addOpts.name,
+	}
+	if addOpts.mapError != nil {
+		handler = &mapErrorHandler[Request, Response]{
+			Handler:  handler,
+			mapError: addOpts.mapError,
+		}
+	}
+	if addOpts.timeout > 0 {
+		handler = &timeoutHandler[Request, Response]{
+			Handler: handler,
+			info:    info,
+			timeout: addOpts.timeout,
+			clock:   hc.opts.clock,
+		}
+	}
+	if addOpts.maxConcurrency > 0 {
+		handler = &concurrencyHandler[Request, Response]{
+			Handler: handler,
+			limiter: newLimiter(addOpts.maxConcurrency, addOpts.concurrencyQueue, hc.opts.clock),
+		}
+	}
+	if addOpts.retryAttempts > 1 {
+		handler = &retryHandler[Request, Response]{
+			Handler:  handler,
+			attempts: addOpts.retryAttempts,
+			backoff:  addOpts.retryBackoff,
+			clock:    hc.opts.clock,
+		}
+	}
+	if addOpts.shadow {
+		handler = &shadowHandler[Request, Response]{
+			Handler: handler,
+			info:    info,
+			errorFn: addOpts.shadowErrorFn,
+		}
+	}
 
 	idHandler := identifiedHandler[Request, Response]{
 		Handler: handler,
-		info: HandlerInfo{
-			ID:   id,
-			Name: addOpts.name,
-		},
+		info:    info,
+		weight:  max(addOpts.deadlineWeight, 0),
+		closer:  closer,
+	}
+
+	if addOpts.fallback {
+		hc.fallbacks = append(hc.fallbacks, idHandler)
+		return id, HandlerID(0), nil
+	}
+
+	if addOpts.fastPath {
+		hc.fastPaths = append(hc.fastPaths, fastPathHandler[Request, Response]{
+			identifiedHandler: idHandler,
+			threshold:         addOpts.fastPathThreshold,
+		})
+		return id, HandlerID(0), nil
 	}
 
 	if addOpts.swapID != HandlerID(0) {
@@ -73,57 +285,343 @@ func (hc *HandlerContainer[Request, Response]) Add(handler Handler[Request, Resp
 			return e.info.ID == addOpts.swapID
 		})
 		if idx >= 0 {
+			hc.retire(hc.stack[idx])
+			hc.stack[idx] = idHandler
+			if hc.stats != nil {
+				hc.stats.forget(addOpts.swapID)
+			}
+			return id, addOpts.swapID, nil
+		}
+	}
+
+	if addOpts.canaryID != HandlerID(0) {
+		idx := slices.IndexFunc(hc.stack, func(e identifiedHandler[Request, Response]) bool {
+			return e.info.ID == addOpts.canaryID
+		})
+		if idx >= 0 {
+			idHandler.Handler = &canaryHandler[Request, Response]{
+				old:       hc.stack[idx].Handler,
+				candidate: handler,
+				percent:   addOpts.canaryPercent,
+				observe:   observe,
+			}
+			// the pair owns the old handler now.
+			idHandler.closer = closers{hc.stack[idx].closer, closer}
 			hc.stack[idx] = idHandler
-			return id
+			if hc.stats != nil {
+				hc.stats.forget(addOpts.canaryID)
+			}
+			return id, addOpts.canaryID, nil
 		}
 	}
 
 	if addOpts.last {
-		slices.Insert(hc.stack, 0, idHandler)
+		hc.stack = slices.Insert(hc.stack, 0, idHandler)
 	} else {
 		hc.stack = append(hc.stack, idHandler)
 	}
 
-	return id
+	return id, HandlerID(0), nil
+}
+
+// replaces reports whether adding a handler with the options would replace
+// a handler instead of growing the container. The lock must be held.
+func (hc *HandlerContainer[Request, Response]) replaces(addOpts *builtAddOptions) bool {
+	if addOpts.fallback || addOpts.fastPath {
+		return false
+	}
+	return slices.ContainsFunc(hc.stack, func(e identifiedHandler[Request, Response]) bool {
+		return e.info.ID != HandlerID(0) && (e.info.ID == addOpts.swapID || e.info.ID == addOpts.canaryID)
+	})
+}
+
+// hasRoomFor reports whether n more handlers can be added without going over
+// the limit set by ContainerOptionMaxHandlers. The lock must be held.
+func (hc *HandlerContainer[Request, Response]) hasRoomFor(n int) bool {
+	if hc.opts.maxHandlers < 1 {
+		return true
+	}
+	return hc.size()+n <= hc.opts.maxHandlers
+}
+
+// size returns the number of handlers in the container, including fallback,
+// fast path, and registered handlers, and finalizers.
+// The lock must be held.
+func (hc *HandlerContainer[Request, Response]) size() int {
+	return len(hc.stack) + len(hc.pinned) + len(hc.fallbacks) + len(hc.fastPaths) + len(hc.finalizers)
 }
 
 // Remove a handler that was previously added.
+// If the handler implements Closer, it's closed once it has been removed.
+// Requests that were already in flight may still be running it.
 func (hc *HandlerContainer[Request, Response]) Remove(id HandlerID) {
-	hc.mux.Lock()
-	defer hc.mux.Unlock()
-	defer hc.buildHandlers()
+	hc.lock()
+	defer hc.unlock()
+	defer hc.invalidate()
+	hc.remove(id)
+}
 
-	hc.stack = slices.DeleteFunc(hc.stack, func(e identifiedHandler[Request, Response]) bool {
-		return e.info.ID == id
+// remove removes a handler without rebuilding the chain.
+// The lock must be held.
+func (hc *HandlerContainer[Request, Response]) remove(id HandlerID) {
+	var removed []HandlerInfo
+	isTarget := func(e identifiedHandler[Request, Response]) bool {
+		if e.info.ID != id {
+			return false
+		}
+		hc.retire(e)
+		removed = append(removed, e.info)
+		return true
+	}
+	hc.stack = slices.DeleteFunc(hc.stack, isTarget)
+	hc.pinned = slices.DeleteFunc(hc.pinned, isTarget)
+	hc.fallbacks = slices.DeleteFunc(hc.fallbacks, isTarget)
+	hc.fastPaths = slices.DeleteFunc(hc.fastPaths, func(e fastPathHandler[Request, Response]) bool {
+		return isTarget(e.identifiedHandler)
 	})
+	hc.finalizers = slices.DeleteFunc(hc.finalizers, func(e finalizer[Request, Response]) bool {
+		return e.id == id
+	})
+	if hc.stats != nil {
+		hc.stats.forget(id)
+	}
+	if hc.opts.logger != nil {
+		for _, info := range removed {
+			hc.logRemove(info)
+		}
+	}
 }
 
 // Handle runs the Handle function of the contained handlers.
 // Handlers that were added latest are executed first.
+// The request is sent through the chain as it was when Handle was called;
+// the container can be mutated while the request is in flight.
+//
+// If the chain returns an error, the request is sent to any fallback
+// handlers (see AddOptionFallback), and then to the error handlers
+// (see ErrorHandlers) if it still fails.
 func (hc *HandlerContainer[Request, Response]) Handle(ctx context.Context, request Request) (Response, error) {
-	hc.mux.RLock()
-	defer hc.mux.RUnlock()
+	return hc.HandleNext(ctx, request, nil)
+}
 
-	return hc.cachedHandler(ctx, request)
+// Func returns Handle as a plain function, for APIs that take one.
+func (hc *HandlerContainer[Request, Response]) Func() func(ctx context.Context, request Request) (Response, error) {
+	return hc.Handle
 }
 
-func (hc *HandlerContainer[Request, Response]) buildHandlers() {
-	// the last functions to be called will be NOPs.
-	curriedHandler := nilCurriedHandlerFunc[Request, Response]
+// HandleNext is like Handle, but if the request falls through the whole
+// chain, next is called instead of returning the zero Response. This lets
+// a container run in the middle of some other chain, like an RPC
+// interceptor, where the rest of that chain is different for every call.
+// A nil next behaves the same as Handle.
+func (hc *HandlerContainer[Request, Response]) HandleNext(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	return hc.serve(ctx, request, next, nil)
+}
 
-	for _, handler := range hc.stack {
-		handler := handler
-		prevHandler := curriedHandler
-		curriedHandler = func(cx context.Context, msg Request) (Response, error) {
-			handlerCtx := contextWithHandlerInfo(cx, handler.info)
-			out, err := handler.Handle(handlerCtx, msg, prevHandler)
-			if err != nil && !errors.Is(err, ErrHandle) {
-				return out, fmt.Errorf("%w handler=%s %w", ErrHandle, handler.info, err)
-			}
+// serve handles a request for HandleNext. If report isn't nil, the version
+// of the chain that was used is written to it.
+func (hc *HandlerContainer[Request, Response]) serve(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response], report *HandleReport) (Response, error) {
+	if err := hc.waitUntilResumed(ctx); err != nil {
+		var zero Response
+		return zero, err
+	}
+	if hc.limiter != nil {
+		if err := hc.limiter.acquire(ctx); err != nil {
+			var zero Response
+			return zero, err
+		}
+		defer hc.limiter.release()
+	}
+	if !hc.enter() {
+		var zero Response
+		return zero, ErrClosed
+	}
+	defer hc.exit()
+
+	if hc.opts.timeout > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = withTimeoutCause(ctx, hc.opts.clock, hc.opts.timeout, nil)
+			defer cancel()
+		}
+	}
+	if next != nil || hasCallNext(ctx) {
+		ctx = contextWithCallNext(ctx, hc, next)
+	}
+	if hc.opts.classifier != nil {
+		ctx = contextWithRetryClassifier(ctx, hc.opts.classifier)
+	}
+
+	snap := hc.loadSnapshot()
+	if report != nil {
+		report.Version = snap.version
+	}
+	if len(snap.finalizers) > 0 {
+		return hc.handleFinally(ctx, request, snap)
+	}
+	return hc.handle(ctx, request, snap)
+}
+
+// handle sends the request through the chain, and then the fallback and
+// error handlers if it fails.
+func (hc *HandlerContainer[Request, Response]) handle(ctx context.Context, request Request, snap *snapshot[Request, Response]) (Response, error) {
+	response, err := hc.handleChain(ctx, request, snap)
+	if err != nil {
+		response, err = hc.handleFallbacks(ctx, request, snap.fallbacks, response, err)
+	}
+	if err == nil {
+		err = hc.validateResponse(response)
+	}
+	if err != nil {
+		if snap.errorHandlers != nil {
+			return snap.errorHandlers.Handle(ctx, Failure[Request, Response]{
+				Request:  request,
+				Response: response,
+				Err:      err,
+			})
+		}
+	}
+	return response, err
+}
+
+// handleChain sends the request through the main chain.
+func (hc *HandlerContainer[Request, Response]) handleChain(ctx context.Context, request Request, snap *snapshot[Request, Response]) (Response, error) {
+	var chain CurriedHandlerFunc[Request, Response]
+	if overrides, ok := hc.overridesFrom(ctx); ok {
+		chain = hc.overriddenChain(overrides)
+	} else if hc.opts.liveChain {
+		hc.mux.RLock()
+		chain = hc.newChain(hc.liveNext(HandlerID(0), -1), hc.pinned).handle
+		hc.mux.RUnlock()
+	} else {
+		chain = snap.chain.handle
+	}
+
+	if hc.opts.bestEffort {
+		collector := &errorCollector{}
+		response, _ := chain(contextWithErrorCollector(ctx, collector), request)
+		return response, collector.join()
+	}
+	response, err := chain(ctx, request)
+	if errors.Is(err, ErrStop) {
+		return response, nil
+	}
+	return response, err
+}
+
+// snapshot is everything Handle needs from a container, as it was after
+// a mutation. It must not be modified once it's stored.
+type snapshot[Request any, Response any] struct {
+	chain         *chain[Request, Response]
+	fallbacks     []identifiedHandler[Request, Response]
+	finalizers    []finalizer[Request, Response]
+	errorHandlers failureHandler[Request, Response]
+	version       uint64
+}
+
+// invalidate discards the current snapshot. Building a snapshot is O(n),
+// so it's put off until the next request instead of being done on every
+// mutation. The lock must be held.
+func (hc *HandlerContainer[Request, Response]) invalidate() {
+	hc.version++
+	hc.lastMutation = hc.opts.clock.Now()
+	hc.current.Store(nil)
+	hc.checkMutation()
+}
+
+// loadSnapshot returns the current snapshot, building it if the container
+// has changed since the last request.
+func (hc *HandlerContainer[Request, Response]) loadSnapshot() *snapshot[Request, Response] {
+	if snap := hc.current.Load(); snap != nil {
+		return snap
+	}
+
+	hc.lock()
+	defer hc.unlock()
+	if snap := hc.current.Load(); snap != nil {
+		// another request built it while we were waiting.
+		return snap
+	}
+	snap := &snapshot[Request, Response]{
+		chain:         hc.chainOf(hc.stack),
+		fallbacks:     slices.Clone(hc.fallbacks),
+		finalizers:    slices.Clone(hc.finalizers),
+		errorHandlers: hc.errorHandlers,
+		version:       hc.version,
+	}
+	hc.current.Store(snap)
+	return snap
+}
+
+// chainOf builds a chain out of a stack of handlers, topped by the pinned
+// handlers. The lock must be held.
+func (hc *HandlerContainer[Request, Response]) chainOf(stack []identifiedHandler[Request, Response]) *chain[Request, Response] {
+	return hc.newChain(hc.fallThrough, hc.bottom, stack, hc.pinned)
+}
+
+// invoke runs a single handler in the chain.
+func (hc *HandlerContainer[Request, Response]) invoke(ctx context.Context, request Request, handler identifiedHandler[Request, Response], next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	handlerCtx := ctx
+	if !hc.opts.noHandlerInfo {
+		handlerCtx = contextWithHandlerInfo(ctx, handler.info)
+	}
+	if trace := traceFrom(handlerCtx); trace != nil {
+		return hc.traced(trace, handlerCtx, request, handler, next)
+	}
+	return hc.profile(handlerCtx, request, handler, next)
+}
+
+// profile runs a single handler in the chain, with pprof labels if they're
+// enabled. ctx must be the context that will be passed to the handler.
+func (hc *HandlerContainer[Request, Response]) profile(handlerCtx context.Context, request Request, handler identifiedHandler[Request, Response], next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	if hc.opts.profilerLabels {
+		return hc.label(handlerCtx, request, handler, next)
+	}
+	return hc.execute(handlerCtx, request, handler, next)
+}
+
+// execute runs a single handler in the chain, notifying any observers.
+// ctx must be the context that will be passed to the handler.
+func (hc *HandlerContainer[Request, Response]) execute(handlerCtx context.Context, request Request, handler identifiedHandler[Request, Response], next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	if len(hc.opts.observers) > 0 || hc.stats != nil {
+		return hc.observe(handlerCtx, request, handler, next)
+	}
+	return hc.run(handlerCtx, request, handler, next)
+}
+
+// run runs a single handler in the chain. ctx must be the context that will
+// be passed to the handler.
+func (hc *HandlerContainer[Request, Response]) run(handlerCtx context.Context, request Request, handler identifiedHandler[Request, Response], next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	if handler.weight > 0 {
+		next = budgetNext(handlerCtx, hc.opts.clock, handler, next)
+	}
+	if hc.opts.bestEffort {
+		return hc.handleBestEffort(handlerCtx, request, handler, next)
+	}
+	if hc.opts.errorDecorator != nil {
+		// custom decorations can't be detected, so track errors from
+		// downstream to avoid decorating them twice.
+		tracker := &nextTracker{}
+		out, err := hc.call(handlerCtx, request, handler, trackNext(tracker, next))
+		if tracker.returned(err) {
 			return out, err
 		}
+		return out, hc.wrapError(handlerCtx, handler.info, err)
+	}
+	out, err := hc.call(handlerCtx, request, handler, next)
+	return out, hc.wrapError(handlerCtx, handler.info, err)
+}
+
+// wrapError decorates an error returned by a handler.
+// ctx must be the context that was passed to the handler.
+func (hc *HandlerContainer[Request, Response]) wrapError(ctx context.Context, info HandlerInfo, err error) error {
+	if err == nil || errors.Is(err, ErrHandle) || errors.Is(err, ErrStop) {
+		return err
 	}
-	hc.cachedHandler = curriedHandler
+	if hc.opts.errorDecorator != nil {
+		return hc.opts.errorDecorator(info, err)
+	}
+	return wrapHandleError[Request](ctx, info, err)
 }
 
 func nilCurriedHandlerFunc[Request any, Response any](ctx context.Context, request Request) (Response, error) {
@@ -131,6 +629,11 @@ func nilCurriedHandlerFunc[Request any, Response any](ctx context.Context, reque
 	return zero, nil
 }
 
+func noHandlersCurriedHandlerFunc[Request any, Response any](ctx context.Context, request Request) (Response, error) {
+	var zero Response
+	return zero, ErrNoHandlers
+}
+
 // Handler processes requests.
 type Handler[Request any, Response any] interface {
 	// Handle runs the handler for the request.
@@ -145,6 +648,14 @@ type CurriedHandlerFunc[Request any, Response any] func(ctx context.Context, req
 type identifiedHandler[Request any, Response any] struct {
 	Handler[Request, Response]
 	info HandlerInfo
+	// weight is the handler's share of the deadline budget.
+	weight float64
+	// downstreamWeight is the total weight of the handlers after this one.
+	// It's set when the chain is built.
+	downstreamWeight float64
+	// closer closes the handler once it leaves the container.
+	// It's nil if the handler doesn't implement Closer.
+	closer Closer
 }
 
 // HandlerInfo contains metadata for a Handler.
@@ -3,8 +3,12 @@ package mutableware_test
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/erinpentecost/mutableware"
 	"github.com/stretchr/testify/require"
@@ -120,6 +124,20 @@ func TestAddLast(t *testing.T) {
 	require.Equal(t, "a", resp)
 }
 
+func TestAddLastOrder(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(appendHandler("a"))
+	hc.AddAnonymousHandler(appendHandler("b"))
+	hc.AddAnonymousHandler(appendHandler("c"), mutableware.AddOptionLast())
+	hc.AddAnonymousHandler(appendHandler("d"), mutableware.AddOptionLast())
+
+	resp, err := hc.HandleNext(context.Background(), "", func(ctx context.Context, request string) (string, error) {
+		return request, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "bacd", resp)
+}
+
 func TestHandleErr(t *testing.T) {
 	expectedErr := fmt.Errorf("an_error")
 	hc := mutableware.NewHandlerContainer[string, any]()
@@ -190,4 +208,437 @@ func TestContext(t *testing.T) {
 	require.Equal(t, expectedSecondStack, mutableware.GetHandlerInfoFromContext(secondHandleCtx))
 
 	require.Equal(t, []mutableware.HandlerInfo{}, mutableware.GetHandlerInfoFromContext(context.Background()))
+
+	current, ok := mutableware.GetCurrentHandlerInfoFromContext(firstHandleCtx)
+	require.True(t, ok)
+	require.Equal(t, expectedFirstStack[1], current)
+	_, ok = mutableware.GetCurrentHandlerInfoFromContext(context.Background())
+	require.False(t, ok)
+}
+
+// TestContextPassthrough confirms that handler contexts still carry the
+// values and cancellation of the context passed to Handle.
+func TestContextPassthrough(t *testing.T) {
+	type key struct{}
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			<-ctx.Done()
+			return ctx.Value(key{}).(string), context.Cause(ctx)
+		})
+	hc.AddAnonymousHandler(nil)
+
+	ctx, cancel := context.WithCancelCause(context.WithValue(context.Background(), key{}, "value"))
+	cause := fmt.Errorf("cancelled")
+	cancel(cause)
+	resp, err := hc.Handle(ctx, "req")
+	require.ErrorIs(t, err, cause)
+	require.Equal(t, "value", resp)
+}
+
+// TestContextFanOut confirms that handlers which call next concurrently
+// don't see each other's handler info.
+func TestContextFanOut(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, []mutableware.HandlerInfo]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, []mutableware.HandlerInfo]) ([]mutableware.HandlerInfo, error) {
+			return mutableware.GetHandlerInfoFromContext(ctx), nil
+		}, mutableware.AddOptionName("leaf"))
+	for i := 0; i < 4; i++ {
+		hc.AddAnonymousHandler(nil)
+	}
+	fanOutID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, []mutableware.HandlerInfo]) ([]mutableware.HandlerInfo, error) {
+			results := make([][]mutableware.HandlerInfo, 16)
+			wg := sync.WaitGroup{}
+			for i := range results {
+				i := i
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					results[i], _ = next(ctx, request)
+				}()
+			}
+			wg.Wait()
+			for _, result := range results[1:] {
+				require.Equal(t, results[0], result)
+			}
+			return results[0], nil
+		})
+
+	stack, err := hc.Handle(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, stack, 6)
+	require.Equal(t, fanOutID, stack[0].ID)
+	require.Equal(t, "leaf", stack[5].Name)
+}
+
+func TestStop(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			require.Fail(t, "should not be reached")
+			return "", nil
+		})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "stopped", fmt.Errorf("done early: %w", mutableware.ErrStop)
+		})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			out, err := next(ctx, request)
+			require.ErrorIs(t, err, mutableware.ErrStop)
+			require.NotErrorIs(t, err, mutableware.ErrHandle)
+			return out, err
+		})
+
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "stopped", resp)
+}
+
+// TestMutateDuringHandle confirms that slow handlers don't block mutations.
+func TestMutateDuringHandle(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			close(started)
+			<-release
+			return "slow", nil
+		})
+
+	result := hc.HandleAsync(context.Background(), "req")
+	<-started
+
+	// this would block forever if Handle held a lock.
+	id := hc.AddAnonymousHandler(nil)
+	hc.Remove(id)
+	close(release)
+
+	require.Equal(t, "slow", (<-result).Response)
+}
+
+// TestSelfMutation confirms that handlers can mutate their own container.
+func TestSelfMutation(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int]) (int, error) {
+			return 0, nil
+		})
+	var onceID mutableware.HandlerID
+	onceID = hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int]) (int, error) {
+			// replace this handler with one that counts differently.
+			hc.AddAnonymousHandler(
+				func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int]) (int, error) {
+					out, err := next(ctx, request)
+					return out + 10, err
+				}, mutableware.AddOptionSwap(onceID))
+			out, err := next(ctx, request)
+			return out + 1, err
+		})
+
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, 1, resp)
+
+	resp, err = hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, 10, resp)
+}
+
+func TestHandleErrorType(t *testing.T) {
+	expectedErr := fmt.Errorf("an_error")
+	hc := mutableware.NewHandlerContainer[string, any]()
+	errHandlerID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, any]) (any, error) {
+			return nil, expectedErr
+		}, mutableware.AddOptionName("failer"))
+	hc.AddAnonymousHandler(nil)
+
+	_, err := hc.Handle(context.Background(), "should throw an error")
+	var handleErr *mutableware.HandleError
+	require.ErrorAs(t, err, &handleErr)
+	require.Equal(t, mutableware.HandlerInfo{ID: errHandlerID, Name: "failer"}, handleErr.Info)
+	require.Equal(t, expectedErr, handleErr.Err)
+	require.Equal(t, "string", handleErr.RequestType.String())
+}
+
+func TestHandleErrorPath(t *testing.T) {
+	expectedErr := fmt.Errorf("an_error")
+	hc := mutableware.NewHandlerContainer[string, any]()
+	errHandlerID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, any]) (any, error) {
+			return nil, expectedErr
+		})
+	outerID := hc.AddAnonymousHandler(nil, mutableware.AddOptionName("outer"))
+
+	_, err := hc.Handle(context.Background(), "should throw an error")
+	var handleErr *mutableware.HandleError
+	require.ErrorAs(t, err, &handleErr)
+	require.Equal(t, []mutableware.HandlerInfo{{ID: outerID, Name: "outer"}, {ID: errHandlerID}}, handleErr.Path)
+	require.Equal(t, "handleError handler=10 an_error", fmt.Sprintf("%v", err))
+	require.Equal(t, "handleError handler=10 path=11(outer)>10 an_error", fmt.Sprintf("%+v", err))
+}
+
+func TestNoErrorWrap(t *testing.T) {
+	expectedErr := fmt.Errorf("an_error")
+	hc := mutableware.NewHandlerContainer[string, any](mutableware.ContainerOptionNoErrorWrap())
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, any]) (any, error) {
+			return nil, expectedErr
+		})
+	hc.AddAnonymousHandler(nil)
+
+	_, err := hc.Handle(context.Background(), "should throw an error")
+	require.Equal(t, expectedErr, err)
+	require.NotErrorIs(t, err, mutableware.ErrHandle)
+}
+
+func TestErrorDecorator(t *testing.T) {
+	expectedErr := fmt.Errorf("an_error")
+	hc := mutableware.NewHandlerContainer[string, any](mutableware.ContainerOptionErrorDecorator(
+		func(info mutableware.HandlerInfo, err error) error {
+			return fmt.Errorf("E%d: %w", info.ID, err)
+		}))
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, any]) (any, error) {
+			return nil, expectedErr
+		})
+	hc.AddAnonymousHandler(nil)
+
+	_, err := hc.Handle(context.Background(), "should throw an error")
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, "E10: an_error", err.Error())
+}
+
+func TestFunc(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return strings.ToUpper(request), nil
+		})
+
+	call := func(fn func(context.Context, string) (string, error)) (string, error) {
+		return fn(context.Background(), "abc")
+	}
+	resp, err := call(hc.Func())
+	require.NoError(t, err)
+	require.Equal(t, "ABC", resp)
+}
+
+// TestConcurrentMutation confirms that each request sees a whole chain,
+// even while the container is being mutated.
+func TestConcurrentMutation(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, int]()
+	addOne := func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+		return next(ctx, request+1)
+	}
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			return request, nil
+		})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			first := hc.AddAnonymousHandler(addOne)
+			second := hc.AddAnonymousHandler(addOne)
+			hc.Remove(first)
+			hc.Remove(second)
+		}
+	}()
+
+	results, err := hc.HandleAll(context.Background(), make([]int, 1000), mutableware.BatchOptionConcurrency(8))
+	require.NoError(t, err)
+	for _, result := range results {
+		require.Contains(t, []int{0, 1, 2}, result)
+	}
+	<-done
+}
+
+func TestWithoutHandlerInfo(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, int](mutableware.ContainerOptionWithoutHandlerInfo())
+	failID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, int]) (int, error) {
+			require.Empty(t, mutableware.GetHandlerInfoFromContext(ctx))
+			return 0, fmt.Errorf("failed")
+		})
+	hc.AddAnonymousHandler(nil)
+
+	_, err := hc.Handle(context.Background(), "req")
+	var handleErr *mutableware.HandleError
+	require.ErrorAs(t, err, &handleErr)
+	require.Equal(t, failID, handleErr.Info.ID)
+	require.Empty(t, handleErr.Path)
+}
+
+func TestMaxHandlers(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, int](mutableware.ContainerOptionMaxHandlers(2))
+	first, err := hc.TryAdd(mutableware.HandlerFunc[string, int](nil).Handler())
+	require.NoError(t, err)
+	hc.AddAnonymousHandler(nil, mutableware.AddOptionFallback())
+
+	_, err = hc.TryAdd(mutableware.HandlerFunc[string, int](nil).Handler())
+	require.ErrorIs(t, err, mutableware.ErrTooManyHandlers)
+	require.Equal(t, mutableware.HandlerID(0), hc.AddAnonymousHandler(nil))
+
+	// swapping doesn't grow the container.
+	swapped, err := hc.TryAdd(mutableware.HandlerFunc[string, int](nil).Handler(), mutableware.AddOptionSwap(first))
+	require.NoError(t, err)
+
+	hc.Remove(swapped)
+	_, err = hc.TryAdd(mutableware.HandlerFunc[string, int](nil).Handler())
+	require.NoError(t, err)
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, time.Time](mutableware.ContainerOptionDefaultTimeout(time.Hour))
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, time.Time]) (time.Time, error) {
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			return deadline, nil
+		})
+
+	deadline, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Minute)
+
+	// deadlines set by the caller are kept.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+	defer cancel()
+	expected, _ := ctx.Deadline()
+	deadline, err = hc.Handle(ctx, "req")
+	require.NoError(t, err)
+	require.Equal(t, expected, deadline)
+}
+
+func TestNoHandlersError(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionNoHandlersError())
+
+	_, err := hc.Handle(context.Background(), "a")
+	require.ErrorIs(t, err, mutableware.ErrNoHandlers)
+
+	hc.AddAnonymousHandler(nil)
+	_, err = hc.Handle(context.Background(), "a")
+	require.ErrorIs(t, err, mutableware.ErrNoHandlers)
+
+	response, err := hc.HandleNext(context.Background(), "a", func(ctx context.Context, request string) (string, error) {
+		return "next", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "next", response)
+
+	hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		return request, nil
+	}, mutableware.AddOptionLast())
+	response, err = hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.Equal(t, "a", response)
+}
+
+func TestDefaultResponse(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionNoHandlersError(),
+		mutableware.ContainerOptionDefaultResponse(func(ctx context.Context, request string) (string, error) {
+			return "default " + request, nil
+		}))
+
+	response, err := hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.Equal(t, "default a", response)
+
+	hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		return next(ctx, request+"!")
+	})
+	response, err = hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.Equal(t, "default a!", response)
+
+	// mismatched types panic.
+	require.Panics(t, func() {
+		mutableware.NewHandlerContainer[string, int](
+			mutableware.ContainerOptionDefaultResponse(func(ctx context.Context, request string) (string, error) {
+				return "default", nil
+			}))
+	})
+}
+
+func TestContainerName(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionName("main"),
+		mutableware.ContainerOptionFirstID(1000))
+	require.Equal(t, "main", hc.Name())
+	require.Equal(t, mutableware.HandlerID(1000), hc.AddAnonymousHandler(nil))
+	require.Equal(t, mutableware.HandlerID(1001), hc.AddAnonymousHandler(nil))
+
+	child := mutableware.NewChildContainer(hc)
+	require.Equal(t, "", child.Name())
+	require.Equal(t, "other", mutableware.NewChildContainer(hc, mutableware.ContainerOptionName("other")).Name())
+}
+
+func TestTerminalHandler(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionFirstID(100),
+		mutableware.ContainerOptionTerminalHandler(mutableware.HandlerFunc[string, string](
+			func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+				return "terminal " + request, nil
+			}).Handler()))
+
+	response, err := hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.Equal(t, "terminal a", response)
+	terminalID := mutableware.HandlerID(math.MaxUint64)
+
+	// handlers added last still run before it, and the first one added
+	// gets the first ID.
+	id := hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		return next(ctx, request+"!")
+	}, mutableware.AddOptionLast())
+	response, err = hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.Equal(t, "terminal a!", response)
+	require.Equal(t, mutableware.HandlerID(100), id)
+	require.Equal(t, []mutableware.HandlerInfo{{ID: id}, {ID: terminalID, Name: "terminal"}}, hc.Layout().Chain)
+
+	// it can't be removed.
+	hc.Remove(terminalID)
+	hc.Remove(id)
+	response, err = hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.Equal(t, "terminal a", response)
+
+	// the child falls through to the parent's terminal handler.
+	child := mutableware.NewChildContainer(hc)
+	response, err = child.Handle(context.Background(), "b")
+	require.NoError(t, err)
+	require.Equal(t, "terminal b", response)
+}
+
+func TestTerminalHandlerTypeMismatch(t *testing.T) {
+	require.Panics(t, func() {
+		mutableware.NewHandlerContainer[string, string](
+			mutableware.ContainerOptionTerminalHandler(mutableware.HandlerFunc[int, string](
+				func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, string]) (string, error) {
+					return "terminal", nil
+				}).Handler()))
+	})
+}
+
+func TestTerminalHandlerLiveChain(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionLiveChain(),
+		mutableware.ContainerOptionTerminalHandler(mutableware.HandlerFunc[string, string](
+			func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+				return "terminal", nil
+			}).Handler()))
+	hc.AddAnonymousHandler(nil)
+
+	response, err := hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.Equal(t, "terminal", response)
 }
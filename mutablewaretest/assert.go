@@ -0,0 +1,143 @@
+package mutablewaretest
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// Container is anything with a Layout, like a *mutableware.HandlerContainer
+// of any Request and Response type.
+type Container interface {
+	Layout() mutableware.Layout
+}
+
+// AssertChainNames fails t unless the names of the handlers in the
+// container's chain are exactly names, in the order they are invoked.
+// Unnamed handlers have an empty name. It reports whether the names matched.
+func AssertChainNames(t TestingT, c Container, names ...string) bool {
+	t.Helper()
+	want := quote(names)
+	chain := c.Layout().Chain
+	got := make([]string, len(chain))
+	for i, info := range chain {
+		got[i] = strconv.Quote(info.Name)
+	}
+	if slices.Equal(want, got) {
+		return true
+	}
+	t.Errorf("mutablewaretest: chain names don't match (-want +got):\n%s", diff(want, got))
+	return false
+}
+
+// AssertHasHandler fails t unless the container holds a handler with id.
+// It reports whether the handler was found.
+func AssertHasHandler(t TestingT, c Container, id mutableware.HandlerID) bool {
+	t.Helper()
+	if _, ok := findHandler(c.Layout(), id); ok {
+		return true
+	}
+	t.Errorf("mutablewaretest: handler %d not found in:\n%s", id, describeLayout(c.Layout()))
+	return false
+}
+
+// AssertNoHandler fails t if the container holds a handler with id.
+// It reports whether the handler was absent.
+func AssertNoHandler(t TestingT, c Container, id mutableware.HandlerID) bool {
+	t.Helper()
+	info, ok := findHandler(c.Layout(), id)
+	if !ok {
+		return true
+	}
+	t.Errorf("mutablewaretest: unexpected handler %s in:\n%s", info, describeLayout(c.Layout()))
+	return false
+}
+
+// AssertSameLayout fails t unless both containers hold handlers with the
+// same names in the same places. IDs aren't compared, since they differ
+// between containers. It reports whether the layouts matched.
+func AssertSameLayout(t TestingT, want Container, got Container) bool {
+	t.Helper()
+	wantLines := layoutLines(want.Layout())
+	gotLines := layoutLines(got.Layout())
+	if slices.Equal(wantLines, gotLines) {
+		return true
+	}
+	t.Errorf("mutablewaretest: layouts don't match (-want +got):\n%s", diff(wantLines, gotLines))
+	return false
+}
+
+func quote(names []string) []string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = strconv.Quote(name)
+	}
+	return quoted
+}
+
+func findHandler(layout mutableware.Layout, id mutableware.HandlerID) (mutableware.HandlerInfo, bool) {
+	for _, section := range sections(layout) {
+		for _, info := range section.infos {
+			if info.ID == id {
+				return info, true
+			}
+		}
+	}
+	return mutableware.HandlerInfo{}, false
+}
+
+type section struct {
+	name  string
+	infos []mutableware.HandlerInfo
+}
+
+func sections(layout mutableware.Layout) []section {
+	return []section{
+		{"chain", layout.Chain},
+		{"fallback", layout.Fallbacks},
+		{"fastPath", layout.FastPaths},
+	}
+}
+
+// layoutLines describes each handler in the layout by its place and name.
+func layoutLines(layout mutableware.Layout) []string {
+	var lines []string
+	for _, section := range sections(layout) {
+		for i, info := range section.infos {
+			lines = append(lines, fmt.Sprintf("%s[%d]: %q", section.name, i, info.Name))
+		}
+	}
+	return lines
+}
+
+func describeLayout(layout mutableware.Layout) string {
+	sb := strings.Builder{}
+	for _, section := range sections(layout) {
+		for i, info := range section.infos {
+			fmt.Fprintf(&sb, "  %s[%d]: %s\n", section.name, i, info)
+		}
+	}
+	return sb.String()
+}
+
+// diff lines up want and got by index, marking lines that differ.
+func diff(want []string, got []string) string {
+	sb := strings.Builder{}
+	for i := 0; i < max(len(want), len(got)); i++ {
+		switch {
+		case i < len(want) && i < len(got) && want[i] == got[i]:
+			fmt.Fprintf(&sb, "  %d: %s\n", i, want[i])
+		default:
+			if i < len(want) {
+				fmt.Fprintf(&sb, "- %d: %s\n", i, want[i])
+			}
+			if i < len(got) {
+				fmt.Fprintf(&sb, "+ %d: %s\n", i, got[i])
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,71 @@
+package mutablewaretest_test
+
+import (
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertChainNames(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(nil, mutableware.AddOptionName("first"))
+	hc.AddAnonymousHandler(nil, mutableware.AddOptionName("second"))
+	hc.AddAnonymousHandler(nil)
+
+	require.True(t, mutablewaretest.AssertChainNames(t, hc, "", "second", "first"))
+
+	fake := &fakeT{}
+	require.False(t, mutablewaretest.AssertChainNames(fake, hc, "", "first"))
+	require.Equal(t, []string{
+		"mutablewaretest: chain names don't match (-want +got):\n" +
+			"  0: \"\"\n" +
+			"- 1: \"first\"\n" +
+			"+ 1: \"second\"\n" +
+			"+ 2: \"first\"\n",
+	}, fake.errors)
+}
+
+func TestAssertHasHandler(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	id := hc.AddAnonymousHandler(nil, mutableware.AddOptionName("fallback"), mutableware.AddOptionFallback())
+
+	require.True(t, mutablewaretest.AssertHasHandler(t, hc, id))
+	fake := &fakeT{}
+	require.False(t, mutablewaretest.AssertNoHandler(fake, hc, id))
+	require.Len(t, fake.errors, 1)
+
+	hc.Remove(id)
+	require.True(t, mutablewaretest.AssertNoHandler(t, hc, id))
+	fake = &fakeT{}
+	require.False(t, mutablewaretest.AssertHasHandler(fake, hc, id))
+	require.Len(t, fake.errors, 1)
+}
+
+func TestAssertSameLayout(t *testing.T) {
+	build := func(fallback string) *mutableware.HandlerContainer[string, string] {
+		hc := mutableware.NewHandlerContainer[string, string]()
+		hc.AddAnonymousHandler(nil, mutableware.AddOptionName("a"))
+		hc.AddAnonymousHandler(nil, mutableware.AddOptionName(fallback), mutableware.AddOptionFallback())
+		return hc
+	}
+	first := build("fallback")
+	first.AddAnonymousHandler(nil, mutableware.AddOptionName("unused"))
+	require.True(t, mutablewaretest.AssertSameLayout(t, first, first))
+
+	second := build("fallback")
+	second.AddAnonymousHandler(nil, mutableware.AddOptionName("unused"))
+	require.True(t, mutablewaretest.AssertSameLayout(t, first, second))
+
+	fake := &fakeT{}
+	require.False(t, mutablewaretest.AssertSameLayout(fake, first, build("other")))
+	require.Equal(t, []string{
+		"mutablewaretest: layouts don't match (-want +got):\n" +
+			"- 0: chain[0]: \"unused\"\n" +
+			"+ 0: chain[0]: \"a\"\n" +
+			"- 1: chain[1]: \"a\"\n" +
+			"+ 1: fallback[0]: \"other\"\n" +
+			"- 2: fallback[0]: \"fallback\"\n",
+	}, fake.errors)
+}
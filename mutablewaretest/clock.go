@@ -0,0 +1,117 @@
+package mutablewaretest
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a mutableware.Clock that only moves when it's told to.
+// Use it with mutableware.ContainerOptionClock to test timeouts and backoff
+// without sleeping. It is safe to use from many goroutines.
+type FakeClock struct {
+	mux     sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	changed chan struct{}
+}
+
+type fakeTimer struct {
+	at time.Time
+	f  func()
+}
+
+// NewFakeClock creates a FakeClock that starts at now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now:     now,
+		changed: make(chan struct{}),
+	}
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.now
+}
+
+// AfterFunc arranges for f to be called by Advance once d has passed.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	timer := &fakeTimer{at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+	close(c.changed)
+	c.changed = make(chan struct{})
+	return func() bool {
+		c.mux.Lock()
+		defer c.mux.Unlock()
+		for i, t := range c.timers {
+			if t == timer {
+				c.timers = append(c.timers[:i], c.timers[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Advance moves the clock forward by d. Functions passed to AfterFunc that
+// come due are called in the order they come due, before Advance returns.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mux.Lock()
+	end := c.now.Add(d)
+	for {
+		next := -1
+		for i, t := range c.timers {
+			if !t.at.After(end) && (next < 0 || t.at.Before(c.timers[next].at)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		timer := c.timers[next]
+		c.timers = append(c.timers[:next], c.timers[next+1:]...)
+		if timer.at.After(c.now) {
+			c.now = timer.at
+		}
+		// f may use the clock.
+		c.mux.Unlock()
+		timer.f()
+		c.mux.Lock()
+	}
+	if end.After(c.now) {
+		c.now = end
+	}
+	c.mux.Unlock()
+}
+
+// Timers returns the number of functions waiting to be called.
+func (c *FakeClock) Timers() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return len(c.timers)
+}
+
+// WaitForTimers blocks until at least n functions are waiting to be called,
+// or until timeout passes in real time. It reports whether there were n.
+// This lets a test wait for code on another goroutine to start waiting on
+// the clock before advancing it.
+func (c *FakeClock) WaitForTimers(n int, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		c.mux.Lock()
+		count, changed := len(c.timers), c.changed
+		c.mux.Unlock()
+		if count >= n {
+			return true
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			return false
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package mutablewaretest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := mutablewaretest.NewFakeClock(start)
+	require.Equal(t, start, clock.Now())
+
+	var fired []string
+	var firedAt []time.Time
+	record := func(name string) func() {
+		return func() {
+			fired = append(fired, name)
+			firedAt = append(firedAt, clock.Now())
+		}
+	}
+	clock.AfterFunc(2*time.Second, record("second"))
+	clock.AfterFunc(time.Second, record("first"))
+	stop := clock.AfterFunc(time.Second, record("stopped"))
+	clock.AfterFunc(time.Minute, record("later"))
+	require.Equal(t, 4, clock.Timers())
+	require.True(t, stop())
+	require.False(t, stop())
+
+	clock.Advance(3 * time.Second)
+	require.Equal(t, []string{"first", "second"}, fired)
+	require.Equal(t, []time.Time{start.Add(time.Second), start.Add(2 * time.Second)}, firedAt)
+	require.Equal(t, start.Add(3*time.Second), clock.Now())
+	require.Equal(t, 1, clock.Timers())
+
+	require.False(t, clock.WaitForTimers(2, time.Millisecond))
+	go clock.AfterFunc(time.Second, func() {})
+	require.True(t, clock.WaitForTimers(2, time.Second))
+}
@@ -0,0 +1,151 @@
+package mutablewaretest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// ErrUnexpectedRequest is returned by a Mock for requests that don't match
+// any of its expectations.
+var ErrUnexpectedRequest = errors.New("unexpected request")
+
+// TestingT is the part of *testing.T that the assertions in this package
+// use.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Mock is a Handler that answers requests according to a list of
+// expectations. It is safe to use from many goroutines.
+type Mock[Request any, Response any] struct {
+	mux          sync.Mutex
+	expectations []*Expectation[Request, Response]
+	unexpected   []Request
+}
+
+// NewMock creates a Mock with no expectations.
+func NewMock[Request any, Response any]() *Mock[Request, Response] {
+	return &Mock[Request, Response]{}
+}
+
+// Expectation describes requests a Mock expects and how it answers them.
+// By default a matching request is passed on to the rest of the chain, and
+// the request must be seen at least once.
+type Expectation[Request any, Response any] struct {
+	mock        *Mock[Request, Response]
+	description string
+	match       func(Request) bool
+	respond     bool
+	response    Response
+	err         error
+	times       int
+	calls       int
+}
+
+// On adds an expectation for requests that match.
+// Expectations are checked in the order they were added, and the first one
+// that matches and hasn't used up its Times answers the request.
+func (m *Mock[Request, Response]) On(match func(Request) bool) *Expectation[Request, Response] {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	e := &Expectation[Request, Response]{
+		mock:        m,
+		description: fmt.Sprintf("expectation %d", len(m.expectations)+1),
+		match:       match,
+	}
+	m.expectations = append(m.expectations, e)
+	return e
+}
+
+// Expect adds an expectation for requests that are deeply equal to request.
+func (m *Mock[Request, Response]) Expect(request Request) *Expectation[Request, Response] {
+	e := m.On(func(r Request) bool {
+		return reflect.DeepEqual(r, request)
+	})
+	e.description = fmt.Sprintf("%+v", request)
+	return e
+}
+
+// Return makes matching requests return response and err instead of
+// calling the rest of the chain.
+func (e *Expectation[Request, Response]) Return(response Response, err error) *Expectation[Request, Response] {
+	e.mock.mux.Lock()
+	defer e.mock.mux.Unlock()
+	e.respond = true
+	e.response = response
+	e.err = err
+	return e
+}
+
+// Times makes the expectation match exactly n requests. Once it has
+// matched n requests, later expectations get a chance to match.
+func (e *Expectation[Request, Response]) Times(n int) *Expectation[Request, Response] {
+	e.mock.mux.Lock()
+	defer e.mock.mux.Unlock()
+	e.times = n
+	return e
+}
+
+// Calls returns the number of requests the expectation has matched.
+func (e *Expectation[Request, Response]) Calls() int {
+	e.mock.mux.Lock()
+	defer e.mock.mux.Unlock()
+	return e.calls
+}
+
+func (m *Mock[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	m.mux.Lock()
+	var found *Expectation[Request, Response]
+	for _, e := range m.expectations {
+		if (e.times <= 0 || e.calls < e.times) && e.match(request) {
+			found = e
+			break
+		}
+	}
+	if found == nil {
+		m.unexpected = append(m.unexpected, request)
+		m.mux.Unlock()
+		var zero Response
+		return zero, fmt.Errorf("%w: %+v", ErrUnexpectedRequest, request)
+	}
+	found.calls++
+	respond, response, err := found.respond, found.response, found.err
+	m.mux.Unlock()
+
+	if respond {
+		return response, err
+	}
+	return next(ctx, request)
+}
+
+// AssertExpectations fails t if any expectation wasn't met, or if the mock
+// saw requests it didn't expect. It reports whether every expectation was
+// met.
+func (m *Mock[Request, Response]) AssertExpectations(t TestingT) bool {
+	t.Helper()
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	ok := true
+	for _, e := range m.expectations {
+		switch {
+		case e.times > 0 && e.calls != e.times:
+			t.Errorf("mutablewaretest: %s: expected %d calls, got %d", e.description, e.times, e.calls)
+			ok = false
+		case e.times <= 0 && e.calls == 0:
+			t.Errorf("mutablewaretest: %s: expected a call, got none", e.description)
+			ok = false
+		}
+	}
+	for _, request := range m.unexpected {
+		t.Errorf("mutablewaretest: unexpected request: %+v", request)
+		ok = false
+	}
+	return ok
+}
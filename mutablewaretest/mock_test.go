@@ -0,0 +1,75 @@
+package mutablewaretest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeT struct {
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMock(t *testing.T) {
+	failed := errors.New("failed")
+	mock := mutablewaretest.NewMock[string, string]()
+	mock.Expect("a").Return("mocked", nil).Times(1)
+	mock.Expect("a").Return("", failed)
+	mock.On(func(request string) bool { return strings.HasPrefix(request, "pass") })
+
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionNoErrorWrap())
+	hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		return "next", nil
+	})
+	hc.Add(mock)
+
+	response, err := hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.Equal(t, "mocked", response)
+
+	_, err = hc.Handle(context.Background(), "a")
+	require.ErrorIs(t, err, failed)
+
+	response, err = hc.Handle(context.Background(), "pass through")
+	require.NoError(t, err)
+	require.Equal(t, "next", response)
+
+	require.True(t, mock.AssertExpectations(t))
+
+	_, err = hc.Handle(context.Background(), "b")
+	require.ErrorIs(t, err, mutablewaretest.ErrUnexpectedRequest)
+
+	fake := &fakeT{}
+	require.False(t, mock.AssertExpectations(fake))
+	require.Equal(t, []string{"mutablewaretest: unexpected request: b"}, fake.errors)
+}
+
+func TestMockUnmetExpectations(t *testing.T) {
+	mock := mutablewaretest.NewMock[string, string]()
+	twice := mock.Expect("a").Times(2)
+	mock.Expect("b")
+
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.Add(mock)
+	_, _ = hc.Handle(context.Background(), "a")
+	require.Equal(t, 1, twice.Calls())
+
+	fake := &fakeT{}
+	require.False(t, mock.AssertExpectations(fake))
+	require.Equal(t, []string{
+		"mutablewaretest: a: expected 2 calls, got 1",
+		"mutablewaretest: b: expected a call, got none",
+	}, fake.errors)
+}
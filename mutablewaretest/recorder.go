@@ -0,0 +1,117 @@
+// Package mutablewaretest provides handlers for testing code that uses
+// mutableware containers.
+package mutablewaretest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// Call is a request seen by a Recorder, along with what the rest of the
+// chain returned for it.
+type Call[Request any, Response any] struct {
+	Request  Request
+	Response Response
+	Err      error
+	// Done is false until the rest of the chain returns. It stays false if
+	// the rest of the chain panics.
+	Done bool
+}
+
+// Recorder is a Handler that passes every request on to the rest of the
+// chain. It records each request as soon as it arrives, and what the chain
+// returned once it's done. It is safe to use from many goroutines.
+type Recorder[Request any, Response any] struct {
+	mux     sync.Mutex
+	calls   []*Call[Request, Response]
+	changed chan struct{}
+}
+
+// NewRecorder creates an empty Recorder.
+func NewRecorder[Request any, Response any]() *Recorder[Request, Response] {
+	return &Recorder[Request, Response]{
+		changed: make(chan struct{}),
+	}
+}
+
+func (r *Recorder[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	call := &Call[Request, Response]{Request: request}
+	r.mux.Lock()
+	r.calls = append(r.calls, call)
+	r.notify()
+	r.mux.Unlock()
+
+	response, err := next(ctx, request)
+	r.mux.Lock()
+	call.Response, call.Err, call.Done = response, err, true
+	r.notify()
+	r.mux.Unlock()
+	return response, err
+}
+
+// notify wakes up WaitFor. The lock must be held.
+func (r *Recorder[Request, Response]) notify() {
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// Calls returns every call recorded so far, in the order they arrived.
+func (r *Recorder[Request, Response]) Calls() []Call[Request, Response] {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	calls := make([]Call[Request, Response], len(r.calls))
+	for i, call := range r.calls {
+		calls[i] = *call
+	}
+	return calls
+}
+
+// Requests returns every request recorded so far, in the order they
+// arrived.
+func (r *Recorder[Request, Response]) Requests() []Request {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	requests := make([]Request, len(r.calls))
+	for i, call := range r.calls {
+		requests[i] = call.Request
+	}
+	return requests
+}
+
+// Len returns the number of calls recorded so far.
+func (r *Recorder[Request, Response]) Len() int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return len(r.calls)
+}
+
+// Reset forgets every recorded call.
+func (r *Recorder[Request, Response]) Reset() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.calls = nil
+}
+
+// WaitFor blocks until at least n calls have been recorded, or until
+// timeout passes. It reports whether n calls were recorded. Calls count as
+// soon as they arrive, so some of them may not be done yet.
+func (r *Recorder[Request, Response]) WaitFor(n int, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		r.mux.Lock()
+		count, changed := len(r.calls), r.changed
+		r.mux.Unlock()
+		if count >= n {
+			return true
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			return false
+		}
+	}
+}
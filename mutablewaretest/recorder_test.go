@@ -0,0 +1,80 @@
+package mutablewaretest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	failed := errors.New("failed")
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionNoErrorWrap())
+	hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		if request == "fail" {
+			return "", failed
+		}
+		return request + "!", nil
+	})
+	recorder := mutablewaretest.NewRecorder[string, string]()
+	hc.Add(recorder)
+
+	require.False(t, recorder.WaitFor(1, time.Millisecond))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = hc.Handle(context.Background(), "a")
+		_, _ = hc.Handle(context.Background(), "fail")
+	}()
+	require.True(t, recorder.WaitFor(2, time.Second))
+	<-done
+
+	require.Equal(t, []string{"a", "fail"}, recorder.Requests())
+	require.Equal(t, []mutablewaretest.Call[string, string]{
+		{Request: "a", Response: "a!", Done: true},
+		{Request: "fail", Err: failed, Done: true},
+	}, recorder.Calls())
+	require.Equal(t, 2, recorder.Len())
+
+	recorder.Reset()
+	require.Empty(t, recorder.Requests())
+	require.Equal(t, 0, recorder.Len())
+}
+
+func TestRecorderInFlight(t *testing.T) {
+	release := make(chan struct{})
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		if request == "panic" {
+			panic("boom")
+		}
+		<-release
+		return request + "!", nil
+	})
+	recorder := mutablewaretest.NewRecorder[string, string]()
+	hc.Add(recorder)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = hc.Handle(context.Background(), "a")
+	}()
+	require.True(t, recorder.WaitFor(1, time.Second))
+	require.Equal(t, []mutablewaretest.Call[string, string]{{Request: "a"}}, recorder.Calls())
+
+	close(release)
+	<-done
+	require.Equal(t, []mutablewaretest.Call[string, string]{{Request: "a", Response: "a!", Done: true}}, recorder.Calls())
+
+	// requests whose downstream panics are still recorded.
+	require.Panics(t, func() {
+		_, _ = hc.Handle(context.Background(), "panic")
+	})
+	require.Equal(t, []string{"a", "panic"}, recorder.Requests())
+	require.False(t, recorder.Calls()[1].Done)
+}
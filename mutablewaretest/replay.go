@@ -0,0 +1,104 @@
+package mutablewaretest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/erinpentecost/mutableware"
+)
+
+// recordedCall is how a Call is serialized. Errors are kept as their
+// message.
+type recordedCall[Request any, Response any] struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+	Err      string   `json:"error,omitempty"`
+}
+
+// Save writes every call that's done so far to w as JSON, so it can be
+// served back later by a Replayer. Requests and Responses must be
+// serializable with encoding/json.
+func (r *Recorder[Request, Response]) Save(w io.Writer) error {
+	recorded := []recordedCall[Request, Response]{}
+	for _, call := range r.Calls() {
+		if !call.Done {
+			continue
+		}
+		saved := recordedCall[Request, Response]{
+			Request:  call.Request,
+			Response: call.Response,
+		}
+		if call.Err != nil {
+			saved.Err = call.Err.Error()
+		}
+		recorded = append(recorded, saved)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(recorded)
+}
+
+// Replayer is a Handler that answers requests with calls saved by a
+// Recorder, instead of calling the rest of the chain. It is safe to use from
+// many goroutines.
+type Replayer[Request any, Response any] struct {
+	mux   sync.Mutex
+	calls []Call[Request, Response]
+	used  []bool
+}
+
+// NewReplayer reads calls saved by Recorder.Save from r.
+func NewReplayer[Request any, Response any](r io.Reader) (*Replayer[Request, Response], error) {
+	var recorded []recordedCall[Request, Response]
+	if err := json.NewDecoder(r).Decode(&recorded); err != nil {
+		return nil, fmt.Errorf("read recording: %w", err)
+	}
+	replayer := &Replayer[Request, Response]{
+		calls: make([]Call[Request, Response], len(recorded)),
+		used:  make([]bool, len(recorded)),
+	}
+	for i, call := range recorded {
+		replayer.calls[i] = Call[Request, Response]{
+			Request:  call.Request,
+			Response: call.Response,
+		}
+		if call.Err != "" {
+			replayer.calls[i].Err = errors.New(call.Err)
+		}
+	}
+	return replayer, nil
+}
+
+// Handle answers the request with the first recorded call for an equal
+// request that hasn't been replayed yet. Requests that have no such call
+// get ErrUnexpectedRequest.
+func (r *Replayer[Request, Response]) Handle(ctx context.Context, request Request, next mutableware.CurriedHandlerFunc[Request, Response]) (Response, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for i, call := range r.calls {
+		if !r.used[i] && reflect.DeepEqual(call.Request, request) {
+			r.used[i] = true
+			return call.Response, call.Err
+		}
+	}
+	var zero Response
+	return zero, fmt.Errorf("%w: %+v", ErrUnexpectedRequest, request)
+}
+
+// Remaining returns the number of recorded calls that haven't been replayed.
+func (r *Replayer[Request, Response]) Remaining() int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	remaining := 0
+	for _, used := range r.used {
+		if !used {
+			remaining++
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,65 @@
+package mutablewaretest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+type lookup struct {
+	Key string
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	external := func(ctx context.Context, request lookup, next mutableware.CurriedHandlerFunc[lookup, int]) (int, error) {
+		calls++
+		if request.Key == "missing" {
+			return 0, errors.New("not found")
+		}
+		return len(request.Key) * calls, nil
+	}
+	requests := []lookup{{Key: "a"}, {Key: "missing"}, {Key: "a"}}
+
+	recording := mutableware.NewHandlerContainer[lookup, int](mutableware.ContainerOptionNoErrorWrap())
+	recording.AddAnonymousHandler(external)
+	recorder := mutablewaretest.NewRecorder[lookup, int]()
+	recording.Add(recorder)
+	for _, request := range requests {
+		_, _ = recording.Handle(context.Background(), request)
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, recorder.Save(buf))
+
+	replayer, err := mutablewaretest.NewReplayer[lookup, int](buf)
+	require.NoError(t, err)
+	require.Equal(t, 3, replayer.Remaining())
+
+	replaying := mutableware.NewHandlerContainer[lookup, int](mutableware.ContainerOptionNoErrorWrap())
+	replaying.Add(replayer)
+
+	response, err := replaying.Handle(context.Background(), lookup{Key: "a"})
+	require.NoError(t, err)
+	require.Equal(t, 1, response)
+	_, err = replaying.Handle(context.Background(), lookup{Key: "missing"})
+	require.EqualError(t, err, "not found")
+	response, err = replaying.Handle(context.Background(), lookup{Key: "a"})
+	require.NoError(t, err)
+	require.Equal(t, 3, response)
+	require.Equal(t, 0, replayer.Remaining())
+	require.Equal(t, 3, calls)
+
+	_, err = replaying.Handle(context.Background(), lookup{Key: "a"})
+	require.ErrorIs(t, err, mutablewaretest.ErrUnexpectedRequest)
+}
+
+func TestReplayerBadRecording(t *testing.T) {
+	_, err := mutablewaretest.NewReplayer[lookup, int](bytes.NewBufferString("not json"))
+	require.Error(t, err)
+}
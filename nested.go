@@ -0,0 +1,44 @@
+package mutableware
+
+import "context"
+
+// containerHandler runs a container as a single handler in another chain.
+type containerHandler[Request any, Response any] struct {
+	hc *HandlerContainer[Request, Response]
+}
+
+// AsHandler returns a Handler that runs the container's chain as a single
+// step in another chain, so containers can be nested. Requests that fall
+// through the container's chain continue down the rest of the outer chain.
+// Errors from the rest of the outer chain pass back up through the container,
+// including its fallback and error handlers.
+func (hc *HandlerContainer[Request, Response]) AsHandler() Handler[Request, Response] {
+	return &containerHandler[Request, Response]{hc: hc}
+}
+
+func (c *containerHandler[Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	return c.hc.HandleNext(ctx, request, next)
+}
+
+// Explain reports the container as active if any of its handlers are.
+func (c *containerHandler[Request, Response]) Explain(ctx context.Context, request Request) bool {
+	for _, explanation := range c.hc.Explain(ctx, request) {
+		if explanation.Active {
+			return true
+		}
+	}
+	return false
+}
+
+// Compose creates a container that runs each of the containers in order.
+// Requests that fall through one container continue on to the next one.
+// The containers are added as handlers with AsHandler, so they can still be
+// changed, and the new container can be changed like any other.
+func Compose[Request any, Response any](containers ...*HandlerContainer[Request, Response]) *HandlerContainer[Request, Response] {
+	composed := NewHandlerContainer[Request, Response]()
+	// handlers that were added latest run first.
+	for i := len(containers) - 1; i >= 0; i-- {
+		composed.Add(containers[i].AsHandler())
+	}
+	return composed
+}
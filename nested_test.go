@@ -0,0 +1,65 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsHandler(t *testing.T) {
+	inner := mutableware.NewHandlerContainer[string, string]()
+	inner.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			if request == "inner" {
+				return "handled by inner", nil
+			}
+			return next(ctx, request)
+		})
+
+	outer := mutableware.NewHandlerContainer[string, string]()
+	outer.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "handled by outer", nil
+		})
+	outer.Add(inner.AsHandler(), mutableware.AddOptionName("inner"))
+
+	resp, err := outer.Handle(context.Background(), "inner")
+	require.NoError(t, err)
+	require.Equal(t, "handled by inner", resp)
+
+	resp, err = outer.Handle(context.Background(), "other")
+	require.NoError(t, err)
+	require.Equal(t, "handled by outer", resp)
+
+	// inner can be changed after it's nested.
+	inner.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "replaced", nil
+		})
+	resp, err = outer.Handle(context.Background(), "other")
+	require.NoError(t, err)
+	require.Equal(t, "replaced", resp)
+}
+
+func TestCompose(t *testing.T) {
+	appendContainer := func(suffix string) *mutableware.HandlerContainer[string, string] {
+		hc := mutableware.NewHandlerContainer[string, string]()
+		hc.AddAnonymousHandler(
+			func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+				return next(ctx, request+suffix)
+			})
+		return hc
+	}
+	terminal := mutableware.NewHandlerContainer[string, string]()
+	terminal.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request, nil
+		})
+
+	composed := mutableware.Compose(appendContainer("a"), appendContainer("b"), terminal)
+	resp, err := composed.Handle(context.Background(), ">")
+	require.NoError(t, err)
+	require.Equal(t, ">ab", resp)
+}
@@ -0,0 +1,61 @@
+package mutableware
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// nextTracker records calls to a handler's next function.
+type nextTracker struct {
+	mux    sync.Mutex
+	called bool
+	errs   []error
+}
+
+// trackNext returns a next function that records its calls in tracker.
+func trackNext[Request any, Response any](tracker *nextTracker, next CurriedHandlerFunc[Request, Response]) CurriedHandlerFunc[Request, Response] {
+	return func(ctx context.Context, request Request) (Response, error) {
+		out, err := next(ctx, request)
+		tracker.record(err)
+		return out, err
+	}
+}
+
+func (nt *nextTracker) record(err error) {
+	nt.mux.Lock()
+	defer nt.mux.Unlock()
+	nt.called = true
+	if err != nil {
+		nt.errs = append(nt.errs, err)
+	}
+}
+
+func (nt *nextTracker) wasCalled() bool {
+	nt.mux.Lock()
+	defer nt.mux.Unlock()
+	return nt.called
+}
+
+// returned is true if err was returned by next.
+func (nt *nextTracker) returned(err error) bool {
+	nt.mux.Lock()
+	defer nt.mux.Unlock()
+	return slices.ContainsFunc(nt.errs, func(e error) bool {
+		return sameError(e, err)
+	})
+}
+
+// sameError is true if a and b are the same error value.
+// Unlike ==, it won't panic on uncomparable error types.
+func sameError(a, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	aType := reflect.TypeOf(a)
+	if aType != reflect.TypeOf(b) || !aType.Comparable() {
+		return false
+	}
+	return a == b
+}
@@ -0,0 +1,35 @@
+package mutableware
+
+import (
+	"context"
+	"time"
+)
+
+// Observer is notified around each handler execution in a container.
+// See ContainerOptionObserver.
+type Observer interface {
+	// OnHandleStart is called before the handler runs. ctx is the context
+	// the handler is given.
+	OnHandleStart(ctx context.Context, info HandlerInfo)
+	// OnHandleEnd is called once the handler returns. duration includes the
+	// time the handler spent waiting on next, and err may have come from
+	// further down the chain.
+	OnHandleEnd(ctx context.Context, info HandlerInfo, duration time.Duration, err error)
+}
+
+// observe runs a single handler in the chain, notifying the observers.
+func (hc *HandlerContainer[Request, Response]) observe(handlerCtx context.Context, request Request, handler identifiedHandler[Request, Response], next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	for _, observer := range hc.opts.observers {
+		observer.OnHandleStart(handlerCtx, handler.info)
+	}
+	start := hc.opts.clock.Now()
+	out, err := hc.run(handlerCtx, request, handler, next)
+	duration := hc.opts.clock.Now().Sub(start)
+	for _, observer := range hc.opts.observers {
+		observer.OnHandleEnd(handlerCtx, handler.info, duration, err)
+	}
+	if hc.stats != nil {
+		hc.stats.OnHandleEnd(handlerCtx, handler.info, duration, err)
+	}
+	return out, err
+}
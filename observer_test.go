@@ -0,0 +1,43 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingObserver struct {
+	events []string
+}
+
+func (o *recordingObserver) OnHandleStart(ctx context.Context, info mutableware.HandlerInfo) {
+	o.events = append(o.events, "start "+info.Name)
+}
+
+func (o *recordingObserver) OnHandleEnd(ctx context.Context, info mutableware.HandlerInfo, duration time.Duration, err error) {
+	o.events = append(o.events, fmt.Sprintf("end %s %t", info.Name, err != nil))
+}
+
+func TestObserver(t *testing.T) {
+	observer := &recordingObserver{}
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionObserver(observer))
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "", errors.New("failed")
+		}, mutableware.AddOptionName("inner"))
+	hc.AddAnonymousHandler(nil, mutableware.AddOptionName("outer"))
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.Error(t, err)
+	require.Equal(t, []string{
+		"start outer",
+		"start inner",
+		"end inner true",
+		"end outer true",
+	}, observer.events)
+}
@@ -0,0 +1,162 @@
+package mutableware
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+// handlerOverrides changes the chain of a container for a single request.
+type handlerOverrides[Request any, Response any] struct {
+	replace   map[HandlerID]Handler[Request, Response]
+	skip      map[HandlerID]struct{}
+	skipNames map[string]struct{}
+	extra     []Handler[Request, Response]
+}
+
+// overrideEntry holds the overrides for one container. Containers can be
+// nested, so each one finds its own entry by walking up through the parents.
+// Entries without an owner hold names to skip in every container.
+type overrideEntry struct {
+	owner     any
+	overrides any
+	parent    *overrideEntry
+}
+
+// overridesFrom returns the overrides for the container, if there are any.
+func (hc *HandlerContainer[Request, Response]) overridesFrom(ctx context.Context) (*handlerOverrides[Request, Response], bool) {
+	var overrides *handlerOverrides[Request, Response]
+	var skipped []map[string]struct{}
+	entry, _ := (ctx.Value(overridesCtxKey)).(*overrideEntry)
+	for ; entry != nil; entry = entry.parent {
+		switch {
+		case entry.owner == nil:
+			skipped = append(skipped, entry.overrides.(map[string]struct{}))
+		case overrides == nil && entry.owner == any(hc):
+			overrides = entry.overrides.(*handlerOverrides[Request, Response])
+		}
+	}
+	if len(skipped) == 0 {
+		return overrides, overrides != nil
+	}
+
+	merged := &handlerOverrides[Request, Response]{
+		replace:   map[HandlerID]Handler[Request, Response]{},
+		skip:      map[HandlerID]struct{}{},
+		skipNames: map[string]struct{}{},
+	}
+	if overrides != nil {
+		merged.replace = overrides.replace
+		merged.skip = overrides.skip
+		merged.skipNames = maps.Clone(overrides.skipNames)
+		merged.extra = overrides.extra
+	}
+	for _, names := range skipped {
+		maps.Copy(merged.skipNames, names)
+	}
+	return merged, true
+}
+
+// withOverrides returns a context with a copy of the container's overrides,
+// changed by apply.
+func (hc *HandlerContainer[Request, Response]) withOverrides(parent context.Context, apply func(o *handlerOverrides[Request, Response])) context.Context {
+	overrides := &handlerOverrides[Request, Response]{
+		replace:   map[HandlerID]Handler[Request, Response]{},
+		skip:      map[HandlerID]struct{}{},
+		skipNames: map[string]struct{}{},
+	}
+	if existing, ok := hc.overridesFrom(parent); ok {
+		overrides.replace = maps.Clone(existing.replace)
+		overrides.skip = maps.Clone(existing.skip)
+		overrides.skipNames = maps.Clone(existing.skipNames)
+		overrides.extra = slices.Clone(existing.extra)
+	}
+	apply(overrides)
+
+	entry := &overrideEntry{
+		owner:     hc,
+		overrides: overrides,
+	}
+	entry.parent, _ = (parent.Value(overridesCtxKey)).(*overrideEntry)
+	return context.WithValue(parent, overridesCtxKey, entry)
+}
+
+// WithHandlerOverride returns a context that makes this container run
+// handler in place of the handler with the ID, for requests sent with it.
+// The container itself isn't changed.
+//
+// Requests with overrides are sent through the chain as it was when Handle
+// was called, even if ContainerOptionLiveChain is used.
+func (hc *HandlerContainer[Request, Response]) WithHandlerOverride(parent context.Context, id HandlerID, handler Handler[Request, Response]) context.Context {
+	return hc.withOverrides(parent, func(o *handlerOverrides[Request, Response]) {
+		o.replace[id] = handler
+	})
+}
+
+// WithHandlerSkipped returns a context that makes this container skip the
+// handler with the ID, for requests sent with it.
+// See WithHandlerOverride.
+func (hc *HandlerContainer[Request, Response]) WithHandlerSkipped(parent context.Context, id HandlerID) context.Context {
+	return hc.withOverrides(parent, func(o *handlerOverrides[Request, Response]) {
+		o.skip[id] = struct{}{}
+	})
+}
+
+// SkipHandlers returns a context that makes every container skip the
+// handlers in its chain that have one of the names, for requests sent with
+// it. Unlike WithHandlerSkipped, this applies to nested containers and
+// containers in other packages too, like skipping a cache for a request
+// that asks for fresh data.
+// See WithHandlerOverride.
+func SkipHandlers(parent context.Context, names ...string) context.Context {
+	skip := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		skip[name] = struct{}{}
+	}
+	entry := &overrideEntry{overrides: skip}
+	entry.parent, _ = (parent.Value(overridesCtxKey)).(*overrideEntry)
+	return context.WithValue(parent, overridesCtxKey, entry)
+}
+
+// WithExtraHandler returns a context that makes this container run handler
+// before the rest of its chain, as if it had just been added, for requests
+// sent with it. See WithHandlerOverride.
+func (hc *HandlerContainer[Request, Response]) WithExtraHandler(parent context.Context, handler Handler[Request, Response]) context.Context {
+	return hc.withOverrides(parent, func(o *handlerOverrides[Request, Response]) {
+		o.extra = append(o.extra, handler)
+	})
+}
+
+// overriddenChain builds a chain for a single request with overrides.
+func (hc *HandlerContainer[Request, Response]) overriddenChain(overrides *handlerOverrides[Request, Response]) CurriedHandlerFunc[Request, Response] {
+	hc.mux.RLock()
+	defer hc.mux.RUnlock()
+
+	stack := overrides.apply(hc.stack, len(overrides.extra))
+	for _, handler := range overrides.extra {
+		stack = append(stack, identifiedHandler[Request, Response]{
+			Handler: handler,
+			info:    HandlerInfo{Name: "extra"},
+		})
+	}
+	return hc.newChain(hc.fallThrough, hc.bottom, stack, overrides.apply(hc.pinned, 0)).handle
+}
+
+// apply returns the handlers that aren't skipped, with their replacements
+// in place. spare is extra room to leave at the end.
+func (o *handlerOverrides[Request, Response]) apply(handlers []identifiedHandler[Request, Response], spare int) []identifiedHandler[Request, Response] {
+	applied := make([]identifiedHandler[Request, Response], 0, len(handlers)+spare)
+	for _, handler := range handlers {
+		if _, ok := o.skip[handler.info.ID]; ok {
+			continue
+		}
+		if _, ok := o.skipNames[handler.info.Name]; ok {
+			continue
+		}
+		if replacement, ok := o.replace[handler.info.ID]; ok {
+			handler.Handler = replacement
+		}
+		applied = append(applied, handler)
+	}
+	return applied
+}
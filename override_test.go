@@ -0,0 +1,110 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func appendHandler(suffix string) mutableware.HandlerFunc[string, string] {
+	return func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+		return next(ctx, request+suffix)
+	}
+}
+
+func TestHandlerOverrides(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request, nil
+		})
+	aID := hc.AddAnonymousHandler(appendHandler("a"))
+	bID := hc.AddAnonymousHandler(appendHandler("b"))
+
+	resp, err := hc.Handle(context.Background(), ">")
+	require.NoError(t, err)
+	require.Equal(t, ">ba", resp)
+
+	ctx := hc.WithHandlerOverride(context.Background(), aID, appendHandler("A").Handler())
+	resp, err = hc.Handle(ctx, ">")
+	require.NoError(t, err)
+	require.Equal(t, ">bA", resp)
+
+	ctx = hc.WithHandlerSkipped(ctx, bID)
+	resp, err = hc.Handle(ctx, ">")
+	require.NoError(t, err)
+	require.Equal(t, ">A", resp)
+
+	ctx = hc.WithExtraHandler(ctx, appendHandler("x").Handler())
+	resp, err = hc.Handle(ctx, ">")
+	require.NoError(t, err)
+	require.Equal(t, ">xA", resp)
+
+	// the container isn't changed.
+	resp, err = hc.Handle(context.Background(), ">")
+	require.NoError(t, err)
+	require.Equal(t, ">ba", resp)
+
+	// other containers aren't affected.
+	other := mutableware.NewHandlerContainer[string, string]()
+	other.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request, nil
+		})
+	other.AddAnonymousHandler(appendHandler("o"))
+	resp, err = other.Handle(ctx, ">")
+	require.NoError(t, err)
+	require.Equal(t, ">o", resp)
+}
+
+func TestSkipHandlers(t *testing.T) {
+	inner := mutableware.NewHandlerContainer[string, string]()
+	inner.AddAnonymousHandler(appendHandler("a"), mutableware.AddOptionName("cache"))
+	inner.AddAnonymousHandler(appendHandler("b"), mutableware.AddOptionName("auth"))
+
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(echo)
+	hc.Add(inner.AsHandler())
+	id := hc.AddAnonymousHandler(appendHandler("c"), mutableware.AddOptionName("cache"))
+	hc.AddAnonymousHandler(appendHandler("d"))
+
+	response, err := hc.Handle(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, "dcba", response)
+
+	ctx := mutableware.SkipHandlers(context.Background(), "cache")
+	response, err = hc.Handle(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, "db", response)
+
+	// combined with the container's own overrides.
+	ctx = hc.WithHandlerSkipped(mutableware.SkipHandlers(context.Background(), "auth"), id)
+	response, err = hc.Handle(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, "da", response)
+}
+
+func TestSkipRegisteredHandlers(t *testing.T) {
+	reg := mutableware.NewRegistry()
+	mutableware.RegisterHandler[string, string](reg, "cache", appendHandler("a").Handler())
+
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionRegistry(reg))
+	hc.AddAnonymousHandler(echo)
+	hc.AddAnonymousHandler(appendHandler("b"))
+
+	response, err := hc.Handle(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, "ab", response)
+
+	ctx := mutableware.SkipHandlers(context.Background(), "cache")
+	response, err = hc.Handle(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, "b", response)
+
+	pinnedID := hc.Layout().Chain[0].ID
+	response, err = hc.Handle(hc.WithHandlerSkipped(context.Background(), pinnedID), "")
+	require.NoError(t, err)
+	require.Equal(t, "b", response)
+}
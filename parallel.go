@@ -0,0 +1,85 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"slices"
+	"sync"
+)
+
+// ParallelMergeFunc combines the responses produced by the members of a
+// parallel group. responses are in the same order as the members were passed
+// to NewParallelGroup. Call next to continue execution down the rest of the chain.
+type ParallelMergeFunc[Request any, Response any] func(ctx context.Context, request Request, responses []Response, next CurriedHandlerFunc[Request, Response]) (Response, error)
+
+// parallelGroup runs a set of independent handlers concurrently.
+type parallelGroup[Request any, Response any] struct {
+	members []Handler[Request, Response]
+	merge   ParallelMergeFunc[Request, Response]
+}
+
+// NewParallelGroup creates a Handler that invokes all of its members
+// concurrently on the same request. Members are terminal within the group:
+// the next function they receive returns the zero Response, so they should
+// not depend on the rest of the chain.
+//
+// Once every member has finished, merge is called with their responses. If
+// merge is nil, the member responses are discarded and execution continues
+// with the rest of the chain. Every member receives the same request value, so
+// members must not mutate it; enrichment should be published through values
+// that are safe for concurrent use.
+//
+// If any member returns an error, the errors are joined and returned without
+// calling merge. A member that panics is reported as a *PanicError, since it
+// runs on its own goroutine where nothing else can recover it.
+func NewParallelGroup[Request any, Response any](merge ParallelMergeFunc[Request, Response], members ...Handler[Request, Response]) Handler[Request, Response] {
+	if merge == nil {
+		merge = passThroughMergeFunc[Request, Response]
+	}
+	return &parallelGroup[Request, Response]{
+		members: slices.Clone(members),
+		merge:   merge,
+	}
+}
+
+func (pg *parallelGroup[Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	responses := make([]Response, len(pg.members))
+	errs := make([]error, len(pg.members))
+
+	wg := sync.WaitGroup{}
+	for i, member := range pg.members {
+		i, member := i, member
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i], errs[i] = pg.run(ctx, member, request)
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		var zero Response
+		return zero, err
+	}
+	return pg.merge(ctx, request, responses, next)
+}
+
+// run invokes a single member, recovering any panic into a *PanicError.
+func (pg *parallelGroup[Request, Response]) run(ctx context.Context, member Handler[Request, Response], request Request) (response Response, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+			panicErr.Info, _ = GetCurrentHandlerInfoFromContext(ctx)
+			err = panicErr
+		}
+	}()
+	return member.Handle(ctx, request, nilCurriedHandlerFunc[Request, Response])
+}
+
+func passThroughMergeFunc[Request any, Response any](ctx context.Context, request Request, responses []Response, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	return next(ctx, request)
+}
@@ -0,0 +1,92 @@
+package mutableware_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelGroupMerge(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "tail", nil
+		})
+	hc.Add(mutableware.NewParallelGroup(
+		func(ctx context.Context, request string, responses []string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			tail, err := next(ctx, request)
+			if err != nil {
+				return "", err
+			}
+			return strings.Join(append(responses, tail), ","), nil
+		},
+		mutableware.HandlerFunc[string, string](func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request + "1", nil
+		}).Handler(),
+		mutableware.HandlerFunc[string, string](func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request + "2", nil
+		}).Handler(),
+	))
+
+	resp, err := hc.Handle(context.Background(), "a")
+	require.NoError(t, err)
+	require.Equal(t, "a1,a2,tail", resp)
+}
+
+func TestParallelGroupEnrich(t *testing.T) {
+	var a, b atomic.Int32
+	hc := mutableware.NewHandlerContainer[int, int32]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int32]) (int32, error) {
+			return a.Load() + b.Load(), nil
+		})
+	hc.Add(mutableware.NewParallelGroup(nil,
+		mutableware.HandlerFunc[int, int32](func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int32]) (int32, error) {
+			a.Store(int32(request))
+			return 0, nil
+		}).Handler(),
+		mutableware.HandlerFunc[int, int32](func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int32]) (int32, error) {
+			b.Store(int32(request * 10))
+			return 0, nil
+		}).Handler(),
+	))
+
+	resp, err := hc.Handle(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, int32(22), resp)
+}
+
+func TestParallelGroupErr(t *testing.T) {
+	expectedErr := fmt.Errorf("an_error")
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.Add(mutableware.NewParallelGroup(nil,
+		mutableware.HandlerFunc[int, int](nil).Handler(),
+		mutableware.HandlerFunc[int, int](func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			return 0, expectedErr
+		}).Handler(),
+	))
+
+	_, err := hc.Handle(context.Background(), 1)
+	require.ErrorIs(t, err, expectedErr)
+	require.ErrorIs(t, err, mutableware.ErrHandle)
+}
+
+func TestParallelGroupPanic(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.Add(mutableware.NewParallelGroup(nil,
+		mutableware.HandlerFunc[int, int](nil).Handler(),
+		mutableware.HandlerFunc[int, int](func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			panic("boom")
+		}).Handler(),
+	))
+
+	_, err := hc.Handle(context.Background(), 1)
+	var panicErr *mutableware.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	require.Equal(t, "boom", panicErr.Value)
+}
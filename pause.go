@@ -0,0 +1,52 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPaused is returned by Handle while the container is paused, if it was
+// created with ContainerOptionRejectWhilePaused.
+var ErrPaused = errors.New("containerPaused")
+
+// Pause stops the container from starting new requests until Resume is
+// called. By default, Handle blocks until the container is resumed or the
+// request's context is done. Requests that are already in flight aren't
+// affected. Pausing a paused container does nothing.
+func (hc *HandlerContainer[Request, Response]) Pause() {
+	hc.mux.Lock()
+	defer hc.mux.Unlock()
+	if hc.paused.Load() == nil {
+		resumed := make(chan struct{})
+		hc.paused.Store(&resumed)
+	}
+}
+
+// Resume lets a paused container start requests again, including those that
+// are blocked waiting for it. Resuming a container that isn't paused does
+// nothing.
+func (hc *HandlerContainer[Request, Response]) Resume() {
+	hc.mux.Lock()
+	defer hc.mux.Unlock()
+	if resumed := hc.paused.Swap(nil); resumed != nil {
+		close(*resumed)
+	}
+}
+
+// waitUntilResumed returns once the container isn't paused.
+func (hc *HandlerContainer[Request, Response]) waitUntilResumed(ctx context.Context) error {
+	for {
+		resumed := hc.paused.Load()
+		if resumed == nil {
+			return nil
+		}
+		if hc.opts.rejectPaused {
+			return ErrPaused
+		}
+		select {
+		case <-*resumed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPause(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request, nil
+		})
+
+	hc.Pause()
+	hc.Pause()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := hc.Handle(ctx, "timeout")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	result := hc.HandleAsync(context.Background(), "blocked")
+	select {
+	case <-result:
+		require.Fail(t, "request should wait for Resume")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	hc.Resume()
+	hc.Resume()
+	require.Equal(t, "blocked", (<-result).Response)
+}
+
+func TestPauseReject(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionRejectWhilePaused())
+	hc.Pause()
+	_, err := hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, mutableware.ErrPaused)
+
+	hc.Resume()
+	_, err = hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+}
@@ -0,0 +1,18 @@
+package mutableware
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// label runs a single handler in the chain with pprof labels identifying it.
+func (hc *HandlerContainer[Request, Response]) label(handlerCtx context.Context, request Request, handler identifiedHandler[Request, Response], next CurriedHandlerFunc[Request, Response]) (out Response, err error) {
+	labels := pprof.Labels(
+		"mutableware.container", hc.opts.profilerName,
+		"mutableware.handler", handler.info.String(),
+	)
+	pprof.Do(handlerCtx, labels, func(labeledCtx context.Context) {
+		out, err = hc.execute(labeledCtx, request, handler, next)
+	})
+	return out, err
+}
@@ -0,0 +1,32 @@
+package mutableware_test
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilerLabels(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionProfilerLabels("test"))
+	innerID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			handler, _ := pprof.Label(ctx, "mutableware.handler")
+			return handler, nil
+		}, mutableware.AddOptionName("inner"))
+	outerID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			inner, err := next(ctx, request)
+			container, _ := pprof.Label(ctx, "mutableware.container")
+			handler, _ := pprof.Label(ctx, "mutableware.handler")
+			return container + " " + handler + " " + inner, err
+		})
+
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	outer := mutableware.HandlerInfo{ID: outerID}.String()
+	inner := mutableware.HandlerInfo{ID: innerID, Name: "inner"}.String()
+	require.Equal(t, "test "+outer+" "+inner, resp)
+}
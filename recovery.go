@@ -0,0 +1,48 @@
+package mutableware
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned in place of a panic when ContainerOptionRecovery is set.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+	// Info identifies the handler that panicked.
+	Info HandlerInfo
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it's an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// call runs the handler's Handle function, recovering from panics if the
+// container is configured to.
+func (hc *HandlerContainer[Request, Response]) call(ctx context.Context, request Request, handler identifiedHandler[Request, Response], next CurriedHandlerFunc[Request, Response]) (out Response, err error) {
+	if hc.opts.recovery {
+		defer func() {
+			if r := recover(); r != nil {
+				var zero Response
+				out = zero
+				err = &PanicError{
+					Value: r,
+					Stack: debug.Stack(),
+					Info:  handler.info,
+				}
+			}
+		}()
+	}
+	return handler.Handle(ctx, request, next)
+}
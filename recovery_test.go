@@ -0,0 +1,29 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovery(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionRecovery())
+	panicID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			panic("oh no")
+		}, mutableware.AddOptionName("panicker"))
+	hc.AddAnonymousHandler(nil)
+
+	resp, err := hc.Handle(context.Background(), "req")
+	require.Zero(t, resp)
+	require.ErrorIs(t, err, mutableware.ErrHandle)
+
+	var panicErr *mutableware.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	require.Equal(t, "oh no", panicErr.Value)
+	require.Equal(t, mutableware.HandlerInfo{ID: panicID, Name: "panicker"}, panicErr.Info)
+	require.Contains(t, string(panicErr.Stack), "recovery_test.go")
+	require.Equal(t, "handleError handler=10(panicker) panic: oh no", err.Error())
+}
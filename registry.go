@@ -0,0 +1,114 @@
+package mutableware
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// AnyHandlerFunc is a handler that works in containers of any type, like
+// logging or metrics. Requests and responses are passed as any, and
+// requests passed to next must keep the container's Request type.
+type AnyHandlerFunc func(ctx context.Context, request any, next func(ctx context.Context, request any) (any, error)) (any, error)
+
+type registryEntry struct {
+	name string
+	// handler is an AnyHandlerFunc or a Handler of some type.
+	handler any
+	options []AddOption
+}
+
+// Registry holds standard handlers that are added to every container
+// created with ContainerOptionRegistry.
+// A Registry is safe for concurrent use.
+type Registry struct {
+	entries []registryEntry
+	mux     *sync.RWMutex
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		mux: &sync.RWMutex{},
+	}
+}
+
+// Register adds a handler that is added to containers of every type.
+// Registered handlers run before every handler that's added to the
+// container directly, so they see every request.
+// name identifies the handler for ContainerOptionSkipRegistered, and is used
+// as its name in each container.
+// Handlers are added to containers in the order they were registered, so
+// newer handlers are invoked first.
+func (r *Registry) Register(name string, handler AnyHandlerFunc, options ...AddOption) {
+	r.register(registryEntry{name: name, handler: handler, options: options})
+}
+
+// RegisterHandler adds a handler to reg that is only added to containers
+// with matching Request and Response types. See Registry.Register.
+func RegisterHandler[Request any, Response any](reg *Registry, name string, handler Handler[Request, Response], options ...AddOption) {
+	reg.register(registryEntry{name: name, handler: handler, options: options})
+}
+
+func (r *Registry) register(entry registryEntry) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+// applyRegistry adds the registered handlers to a new container, pinned
+// above the rest of the chain.
+func (hc *HandlerContainer[Request, Response]) applyRegistry() {
+	reg := hc.opts.registry
+	reg.mux.RLock()
+	entries := slices.Clone(reg.entries)
+	reg.mux.RUnlock()
+
+	for _, entry := range entries {
+		if slices.Contains(hc.opts.skipRegistered, entry.name) {
+			continue
+		}
+		var handler Handler[Request, Response]
+		switch h := entry.handler.(type) {
+		case Handler[Request, Response]:
+			handler = h
+		case AnyHandlerFunc:
+			handler = &anyHandler[Request, Response]{handlerFn: h}
+		default:
+			continue
+		}
+		options := append([]AddOption{AddOptionName(entry.name)}, entry.options...)
+		id, err := hc.add(handler, options)
+		if err != nil {
+			continue
+		}
+		if idx := slices.IndexFunc(hc.stack, func(e identifiedHandler[Request, Response]) bool {
+			return e.info.ID == id
+		}); idx >= 0 {
+			// registered handlers are shared between containers, so
+			// none of them can close it.
+			hc.stack[idx].closer = nil
+			hc.pinned = append(hc.pinned, hc.stack[idx])
+			hc.stack = slices.Delete(hc.stack, idx, idx+1)
+		}
+	}
+}
+
+// anyHandler runs an AnyHandlerFunc in a typed chain.
+type anyHandler[Request any, Response any] struct {
+	handlerFn AnyHandlerFunc
+}
+
+func (a *anyHandler[Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	out, err := a.handlerFn(ctx, request, func(ctx context.Context, request any) (any, error) {
+		typed, ok := request.(Request)
+		if !ok {
+			var zero Response
+			return zero, fmt.Errorf("request type changed to %T", request)
+		}
+		return next(ctx, typed)
+	})
+	response, _ := out.(Response)
+	return response, err
+}
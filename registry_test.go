@@ -0,0 +1,47 @@
+package mutableware_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	logged := []string{}
+	reg := mutableware.NewRegistry()
+	reg.Register("logging", func(ctx context.Context, request any, next func(context.Context, any) (any, error)) (any, error) {
+		logged = append(logged, fmt.Sprint(request))
+		return next(ctx, request)
+	})
+	mutableware.RegisterHandler[string, string](reg, "suffix", appendHandler("!").Handler())
+
+	strs := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionRegistry(reg))
+	strs.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return next(ctx, request+">handled")
+		})
+	ints := mutableware.NewHandlerContainer[int, int](mutableware.ContainerOptionRegistry(reg))
+	optedOut := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionRegistry(reg),
+		mutableware.ContainerOptionSkipRegistered("logging"))
+
+	resp, err := strs.HandleNext(context.Background(), "a", func(ctx context.Context, request string) (string, error) {
+		return request, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "a!>handled", resp)
+
+	_, err = ints.Handle(context.Background(), 1)
+	require.NoError(t, err)
+	_, err = optedOut.Handle(context.Background(), "b")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"a!", "1"}, logged)
+
+	explanations := strs.Explain(context.Background(), "a")
+	require.Equal(t, "suffix", explanations[0].Info.Name)
+	require.Equal(t, "logging", explanations[1].Info.Name)
+}
@@ -0,0 +1,57 @@
+package mutableware
+
+import "context"
+
+// HandleReport describes how a request was handled. See HandleDetailed.
+type HandleReport struct {
+	// Version identifies the state of the container when the request was
+	// handled. It changes every time the container is changed, so reports
+	// with the same Version went through the same chain.
+	Version uint64
+	// Path lists the handlers that ran, in the order they were started.
+	Path []HandlerInfo
+	// Trace has the timing of each handler in Path.
+	Trace []ExecutionTraceEntry
+	// Responder is the handler that produced the response, which is the
+	// last handler to run without calling next. Responded is false if the
+	// request fell through every handler.
+	Responder HandlerInfo
+	Responded bool
+}
+
+// HandleDetailed is like Handle, but also reports which handlers ran, how
+// long they took, and which of them produced the response.
+// The report includes handlers in containers the request passes through,
+// like error handlers and nested containers.
+func (hc *HandlerContainer[Request, Response]) HandleDetailed(ctx context.Context, request Request) (Response, HandleReport, error) {
+	report := HandleReport{}
+	ctx = ContextWithExecutionTrace(ctx)
+	response, err := hc.serve(ctx, request, nil, &report)
+
+	report.Trace = GetExecutionTraceFromContext(ctx)
+	report.Path = make([]HandlerInfo, 0, len(report.Trace))
+	for _, entry := range report.Trace {
+		report.Path = append(report.Path, entry.Info)
+	}
+	report.Responder, report.Responded = responder(report.Trace)
+	return response, report, err
+}
+
+// Handled reports which handler produced the response to the request that
+// was sent with ctx, which must come from ContextWithExecutionTrace. It
+// returns false if the request fell through every handler, so a zero
+// Response that a handler chose to return can be told apart from one that
+// nothing returned. See HandleReport.Responder.
+func Handled(ctx context.Context) (HandlerInfo, bool) {
+	return responder(GetExecutionTraceFromContext(ctx))
+}
+
+// responder returns the last handler in the trace that didn't call next.
+func responder(trace []ExecutionTraceEntry) (HandlerInfo, bool) {
+	for i := len(trace) - 1; i >= 0; i-- {
+		if !trace[i].CalledNext {
+			return trace[i].Info, true
+		}
+	}
+	return HandlerInfo{}, false
+}
@@ -0,0 +1,104 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleDetailed(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	responderID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			if request == "fallthrough" {
+				return next(ctx, request)
+			}
+			return "answer", nil
+		}, mutableware.AddOptionName("responder"))
+	outerID := hc.AddAnonymousHandler(nil)
+
+	resp, report, err := hc.HandleDetailed(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "answer", resp)
+	require.Equal(t, []mutableware.HandlerInfo{{ID: outerID}, {ID: responderID, Name: "responder"}}, report.Path)
+	require.Len(t, report.Trace, 2)
+	require.True(t, report.Responded)
+	require.Equal(t, responderID, report.Responder.ID)
+
+	_, report, err = hc.HandleDetailed(context.Background(), "fallthrough")
+	require.NoError(t, err)
+	require.False(t, report.Responded)
+	version := report.Version
+
+	// the version only changes when the container does.
+	_, report, _ = hc.HandleDetailed(context.Background(), "req")
+	require.Equal(t, version, report.Version)
+	hc.Remove(outerID)
+	_, report, _ = hc.HandleDetailed(context.Background(), "req")
+	require.NotEqual(t, version, report.Version)
+	require.Equal(t, []mutableware.HandlerInfo{{ID: responderID, Name: "responder"}}, report.Path)
+}
+
+func TestHandled(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	zeroID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			if request == "zero" {
+				return "", nil
+			}
+			return next(ctx, request)
+		}, mutableware.AddOptionName("zero"))
+	hc.AddAnonymousHandler(nil)
+
+	ctx := mutableware.ContextWithExecutionTrace(context.Background())
+	resp, err := hc.Handle(ctx, "zero")
+	require.NoError(t, err)
+	require.Equal(t, "", resp)
+	info, ok := mutableware.Handled(ctx)
+	require.True(t, ok)
+	require.Equal(t, mutableware.HandlerInfo{ID: zeroID, Name: "zero"}, info)
+
+	ctx = mutableware.ContextWithExecutionTrace(context.Background())
+	resp, err = hc.Handle(ctx, "other")
+	require.NoError(t, err)
+	require.Equal(t, "", resp)
+	_, ok = mutableware.Handled(ctx)
+	require.False(t, ok)
+
+	// without a trace, nothing is known.
+	_, ok = mutableware.Handled(context.Background())
+	require.False(t, ok)
+}
+
+func TestHandleDetailedNested(t *testing.T) {
+	inner := mutableware.NewHandlerContainer[string, string]()
+	responderID := inner.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "inner", nil
+		}, mutableware.AddOptionName("responder"))
+
+	var report mutableware.HandleReport
+	var handled bool
+	outer := mutableware.NewHandlerContainer[string, string]()
+	outer.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			traceCtx := mutableware.ContextWithExecutionTrace(ctx)
+			_, _ = inner.Handle(traceCtx, request)
+			_, handled = mutableware.Handled(traceCtx)
+
+			var resp string
+			var err error
+			resp, report, err = inner.HandleDetailed(ctx, request)
+			return resp, err
+		})
+
+	resp, err := outer.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "inner", resp)
+	require.True(t, handled)
+	require.True(t, report.Responded)
+	require.Equal(t, responderID, report.Responder.ID)
+	require.Equal(t, []mutableware.HandlerInfo{{ID: responderID, Name: "responder"}}, report.Path)
+}
@@ -0,0 +1,30 @@
+package mutableware
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidResponse is returned when a response fails the check set by
+// ContainerOptionValidateResponse.
+var ErrInvalidResponse = errors.New("invalidResponse")
+
+// validateResponse checks a successful response against the container's
+// response validator, if it has one.
+func (hc *HandlerContainer[Request, Response]) validateResponse(response Response) error {
+	validate, ok := hc.opts.validate.(func(Response) error)
+	if !ok {
+		return nil
+	}
+	if err := validate(response); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+	return nil
+}
+
+func validateMethod[Response any](response Response) error {
+	if validator, ok := any(response).(interface{ Validate() error }); ok {
+		return validator.Validate()
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+type quote struct {
+	Price int
+}
+
+func (q quote) Validate() error {
+	if q.Price < 0 {
+		return errors.New("negative price")
+	}
+	return nil
+}
+
+func TestValidateResponse(t *testing.T) {
+	tooLong := errors.New("too long")
+	hc := mutableware.NewHandlerContainer[string, string](
+		mutableware.ContainerOptionValidateResponse(func(r string) error {
+			if len(r) > 3 {
+				return tooLong
+			}
+			return nil
+		}))
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return request, nil
+		})
+
+	resp, err := hc.Handle(context.Background(), "abc")
+	require.NoError(t, err)
+	require.Equal(t, "abc", resp)
+
+	_, err = hc.Handle(context.Background(), "abcd")
+	require.ErrorIs(t, err, mutableware.ErrInvalidResponse)
+	require.ErrorIs(t, err, tooLong)
+}
+
+func TestValidateResponseMethod(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, quote](
+		mutableware.ContainerOptionValidateResponse[quote](nil))
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, quote]) (quote, error) {
+			if request < 0 {
+				return quote{}, errors.New("unpriced")
+			}
+			return quote{Price: request}, nil
+		})
+	// fallbacks are checked too
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, quote]) (quote, error) {
+			return quote{Price: -1}, nil
+		}, mutableware.AddOptionFallback())
+
+	_, err := hc.Handle(context.Background(), 5)
+	require.NoError(t, err)
+
+	_, err = hc.Handle(context.Background(), -5)
+	require.ErrorIs(t, err, mutableware.ErrInvalidResponse)
+}
+
+func TestValidateResponseTypeMismatch(t *testing.T) {
+	require.Panics(t, func() {
+		mutableware.NewHandlerContainer[string, string](
+			mutableware.ContainerOptionValidateResponse(func(r int) error { return nil }))
+	})
+}
@@ -0,0 +1,60 @@
+package mutableware
+
+import (
+	"context"
+	"time"
+)
+
+// ConstantBackoff waits the same duration before every attempt.
+func ConstantBackoff(d time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff doubles the wait before each attempt, starting
+// at base and never exceeding limit.
+func ExponentialBackoff(base time.Duration, limit time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < limit; i++ {
+			d *= 2
+		}
+		return min(d, limit)
+	}
+}
+
+// retryHandler re-runs a handler that fails.
+type retryHandler[Request any, Response any] struct {
+	Handler[Request, Response]
+	attempts int
+	backoff  BackoffFunc
+	clock    Clock
+}
+
+func (r *retryHandler[Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	for attempt := 1; ; attempt++ {
+		response, err := r.Handler.Handle(ctx, request, next)
+		if err == nil || attempt >= r.attempts || !IsRetryable(ctx, err) {
+			return response, err
+		}
+
+		var wait time.Duration
+		if r.backoff != nil {
+			wait = r.backoff(attempt)
+		}
+		if wait <= 0 {
+			if ctx.Err() != nil {
+				return response, err
+			}
+			continue
+		}
+		waited, stop := clockTimer(r.clock, wait)
+		select {
+		case <-ctx.Done():
+			stop()
+			return response, err
+		case <-waited:
+		}
+	}
+}
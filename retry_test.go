@@ -0,0 +1,80 @@
+package mutableware_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/erinpentecost/mutableware/mutablewaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	expectedErr := fmt.Errorf("an_error")
+	downstreamCalls := 0
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			downstreamCalls++
+			if downstreamCalls < 3 {
+				return "", expectedErr
+			}
+			return "ok", nil
+		})
+	hc.AddAnonymousHandler(nil, mutableware.AddOptionRetry(3, mutableware.ConstantBackoff(time.Millisecond)))
+
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+	require.Equal(t, 3, downstreamCalls)
+
+	downstreamCalls = -10
+	_, err = hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, -7, downstreamCalls)
+}
+
+func TestRetryImmediately(t *testing.T) {
+	// retrying without a backoff doesn't wait on the clock at all.
+	clock := mutablewaretest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	calls := 0
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionClock(clock))
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			calls++
+			if calls < 3 {
+				return "", fmt.Errorf("an_error")
+			}
+			return "ok", nil
+		}, mutableware.AddOptionRetry(3, nil))
+
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+	require.Equal(t, 3, calls)
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	calls := 0
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			calls++
+			return "", context.Canceled
+		}, mutableware.AddOptionRetry(5, nil))
+
+	_, err := hc.Handle(context.Background(), "req")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, calls)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := mutableware.ExponentialBackoff(time.Millisecond, 5*time.Millisecond)
+	require.Equal(t, time.Millisecond, backoff(1))
+	require.Equal(t, 2*time.Millisecond, backoff(2))
+	require.Equal(t, 4*time.Millisecond, backoff(3))
+	require.Equal(t, 5*time.Millisecond, backoff(4))
+	require.Equal(t, 5*time.Millisecond, backoff(40))
+}
@@ -0,0 +1,93 @@
+package mutableware
+
+import (
+	"context"
+	"sync"
+)
+
+// Router sends each request to a container chosen by the request's key.
+// Routes can be added and removed while requests are in flight.
+// A Router is safe for concurrent use.
+type Router[K comparable, Request any, Response any] struct {
+	key          func(Request) K
+	routes       map[K]*HandlerContainer[Request, Response]
+	defaultRoute *HandlerContainer[Request, Response]
+	mux          *sync.RWMutex
+}
+
+// NewRouter creates a Router that routes requests by the value key returns
+// for them. Requests that don't match a route are sent to defaultRoute,
+// which can be nil.
+func NewRouter[K comparable, Request any, Response any](key func(Request) K, defaultRoute *HandlerContainer[Request, Response]) *Router[K, Request, Response] {
+	return &Router[K, Request, Response]{
+		key:          key,
+		routes:       map[K]*HandlerContainer[Request, Response]{},
+		defaultRoute: defaultRoute,
+		mux:          &sync.RWMutex{},
+	}
+}
+
+// AddRoute sends requests with the key to hc, replacing any route that
+// already exists for it.
+func (r *Router[K, Request, Response]) AddRoute(key K, hc *HandlerContainer[Request, Response]) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.routes[key] = hc
+}
+
+// RemoveRoute removes the route for the key, so its requests go to the
+// default container. It returns false if there was no such route.
+func (r *Router[K, Request, Response]) RemoveRoute(key K) bool {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	_, ok := r.routes[key]
+	delete(r.routes, key)
+	return ok
+}
+
+// Route returns the container that requests with the key are sent to.
+// If there is none, it returns false.
+func (r *Router[K, Request, Response]) Route(key K) (*HandlerContainer[Request, Response], bool) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	if hc, ok := r.routes[key]; ok {
+		return hc, true
+	}
+	return r.defaultRoute, r.defaultRoute != nil
+}
+
+// Handle sends the request to the container for its key.
+// If there is no such container, the zero Response is returned.
+func (r *Router[K, Request, Response]) Handle(ctx context.Context, request Request) (Response, error) {
+	return r.HandleNext(ctx, request, nil)
+}
+
+// HandleNext is like Handle, but calls next if the request falls through
+// the container for its key, or if there is no such container.
+// See HandlerContainer.HandleNext.
+func (r *Router[K, Request, Response]) HandleNext(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	hc, ok := r.Route(r.key(request))
+	if !ok {
+		if next == nil {
+			next = nilCurriedHandlerFunc[Request, Response]
+		}
+		return next(ctx, request)
+	}
+	return hc.HandleNext(ctx, request, next)
+}
+
+// routerHandler runs a Router as a single handler in a chain.
+type routerHandler[K comparable, Request any, Response any] struct {
+	r *Router[K, Request, Response]
+}
+
+// AsHandler returns a Handler that routes requests as a single step in a
+// chain. Requests that fall through their route continue down the rest of
+// the chain.
+func (r *Router[K, Request, Response]) AsHandler() Handler[Request, Response] {
+	return &routerHandler[K, Request, Response]{r: r}
+}
+
+func (rh *routerHandler[K, Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	return rh.r.HandleNext(ctx, request, next)
+}
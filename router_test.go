@@ -0,0 +1,58 @@
+package mutableware_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func respondWith(response string) *mutableware.HandlerContainer[string, string] {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return response, nil
+		})
+	return hc
+}
+
+func TestRouter(t *testing.T) {
+	command := func(request string) string {
+		return strings.SplitN(request, " ", 2)[0]
+	}
+	router := mutableware.NewRouter(command, respondWith("unknown"))
+	router.AddRoute("get", respondWith("got"))
+	router.AddRoute("put", respondWith("put"))
+
+	resp, err := router.Handle(context.Background(), "get a")
+	require.NoError(t, err)
+	require.Equal(t, "got", resp)
+
+	resp, err = router.Handle(context.Background(), "delete a")
+	require.NoError(t, err)
+	require.Equal(t, "unknown", resp)
+
+	require.True(t, router.RemoveRoute("get"))
+	require.False(t, router.RemoveRoute("get"))
+	resp, err = router.Handle(context.Background(), "get a")
+	require.NoError(t, err)
+	require.Equal(t, "unknown", resp)
+}
+
+func TestRouterAsHandler(t *testing.T) {
+	router := mutableware.NewRouter[string, string, string](func(r string) string { return r }, nil)
+	router.AddRoute("routed", respondWith("from route"))
+
+	hc := respondWith("from chain")
+	hc.Add(router.AsHandler())
+
+	resp, err := hc.Handle(context.Background(), "routed")
+	require.NoError(t, err)
+	require.Equal(t, "from route", resp)
+
+	resp, err = hc.Handle(context.Background(), "unrouted")
+	require.NoError(t, err)
+	require.Equal(t, "from chain", resp)
+}
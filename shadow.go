@@ -0,0 +1,41 @@
+package mutableware
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// shadowHandler runs a handler asynchronously without letting it
+// participate in the chain.
+type shadowHandler[Request any, Response any] struct {
+	Handler[Request, Response]
+	info    HandlerInfo
+	errorFn ShadowErrorFunc
+}
+
+func (s *shadowHandler[Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	shadowCtx := context.WithoutCancel(ctx)
+	go func() {
+		err := s.run(shadowCtx, request)
+		if err != nil && s.errorFn != nil {
+			s.errorFn(shadowCtx, s.info, err)
+		}
+	}()
+	return next(ctx, request)
+}
+
+// run invokes the shadowed handler. Nothing can recover a panic on the
+// shadow's goroutine, so panics are always reported as a *PanicError.
+func (s *shadowHandler[Request, Response]) run(ctx context.Context, request Request) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+				Info:  s.info,
+			}
+		}
+	}()
+	_, err = s.Handler.Handle(ctx, request, nilCurriedHandlerFunc[Request, Response])
+	return err
+}
@@ -0,0 +1,39 @@
+package mutableware_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShadow(t *testing.T) {
+	expectedErr := fmt.Errorf("an_error")
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "primary", nil
+		})
+
+	seen := make(chan string, 1)
+	reported := make(chan mutableware.HandlerInfo, 1)
+	shadowID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			seen <- request
+			return "shadow", expectedErr
+		},
+		mutableware.AddOptionShadow(func(ctx context.Context, info mutableware.HandlerInfo, err error) {
+			require.ErrorIs(t, err, expectedErr)
+			reported <- info
+		}),
+		mutableware.AddOptionName("candidate"),
+	)
+
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "primary", resp)
+	require.Equal(t, "req", <-seen)
+	require.Equal(t, mutableware.HandlerInfo{ID: shadowID, Name: "candidate"}, <-reported)
+}
@@ -0,0 +1,102 @@
+package mutableware
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+)
+
+// Sharded splits handlers between several containers, so that mutations
+// in different shards don't wait on each other. Each request is handled by
+// the shard for its key, and a key always maps to the same shard, so
+// handlers that are only needed for some keys (like the handlers for a
+// tenant) can be added to the shard for that key.
+// A Sharded is safe for concurrent use.
+type Sharded[Request any, Response any] struct {
+	key    func(Request) string
+	seed   maphash.Seed
+	shards []*HandlerContainer[Request, Response]
+}
+
+// NewSharded creates a Sharded with the number of shards, which routes
+// requests by the value key returns for them. Every shard is created with
+// the options, except that with ContainerOptionExpvar each shard is
+// published under the name followed by "." and its index in Shards.
+// Values of n less than 1 mean one shard.
+func NewSharded[Request any, Response any](n int, key func(Request) string, options ...ContainerOption) *Sharded[Request, Response] {
+	s := &Sharded[Request, Response]{
+		key:    key,
+		seed:   maphash.MakeSeed(),
+		shards: make([]*HandlerContainer[Request, Response], max(n, 1)),
+	}
+	for i := range s.shards {
+		opts := buildContainerOptions(options)
+		if opts.expvarName != "" {
+			opts.expvarName = fmt.Sprintf("%s.%d", opts.expvarName, i)
+		}
+		s.shards[i] = newHandlerContainer[Request, Response](opts)
+	}
+	return s
+}
+
+// For returns the shard that handles requests with the key.
+// Handlers added to it see requests for every key in the shard, not just
+// this one.
+func (s *Sharded[Request, Response]) For(key string) *HandlerContainer[Request, Response] {
+	return s.shards[maphash.String(s.seed, key)%uint64(len(s.shards))]
+}
+
+// Shards returns every shard, for handlers that should see all requests.
+func (s *Sharded[Request, Response]) Shards() []*HandlerContainer[Request, Response] {
+	return s.shards
+}
+
+// AddAll adds the handler to every shard. It returns the ID of the handler
+// in each shard, in the same order as Shards.
+// Every shard shares the one handler, so it must be safe for concurrent use.
+// If it implements Initializer or Closer, Init is called once per shard, and
+// so is Close when it's removed or the shards are closed. Add a separate
+// handler to each shard instead if that's a problem.
+func (s *Sharded[Request, Response]) AddAll(handler Handler[Request, Response], options ...AddOption) []HandlerID {
+	ids := make([]HandlerID, len(s.shards))
+	for i, shard := range s.shards {
+		ids[i] = shard.Add(handler, options...)
+	}
+	return ids
+}
+
+// RemoveAll removes handlers that were added with AddAll.
+func (s *Sharded[Request, Response]) RemoveAll(ids []HandlerID) {
+	for i, id := range ids {
+		if i < len(s.shards) {
+			s.shards[i].Remove(id)
+		}
+	}
+}
+
+// Handle sends the request to the shard for its key.
+func (s *Sharded[Request, Response]) Handle(ctx context.Context, request Request) (Response, error) {
+	return s.HandleNext(ctx, request, nil)
+}
+
+// HandleNext is like Handle, but calls next if the request falls through
+// its shard. See HandlerContainer.HandleNext.
+func (s *Sharded[Request, Response]) HandleNext(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	return s.For(s.key(request)).HandleNext(ctx, request, next)
+}
+
+// shardedHandler runs a Sharded as a single handler in a chain.
+type shardedHandler[Request any, Response any] struct {
+	s *Sharded[Request, Response]
+}
+
+// AsHandler returns a Handler that sends requests to their shard as a
+// single step in a chain. Requests that fall through their shard continue
+// down the rest of the chain.
+func (s *Sharded[Request, Response]) AsHandler() Handler[Request, Response] {
+	return &shardedHandler[Request, Response]{s: s}
+}
+
+func (sh *shardedHandler[Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	return sh.s.HandleNext(ctx, request, next)
+}
@@ -0,0 +1,78 @@
+package mutableware_test
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharded(t *testing.T) {
+	tenant := func(request string) string {
+		return strings.SplitN(request, "/", 2)[0]
+	}
+	sharded := mutableware.NewSharded[string, string](8, tenant)
+	require.Len(t, sharded.Shards(), 8)
+
+	ids := sharded.AddAll(mutableware.HandlerFunc[string, string](
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			return "global", nil
+		}).Handler())
+	sharded.For("a").AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			if tenant(request) != "a" {
+				return next(ctx, request)
+			}
+			return "a", nil
+		})
+
+	resp, err := sharded.Handle(context.Background(), "a/req")
+	require.NoError(t, err)
+	require.Equal(t, "a", resp)
+
+	resp, err = sharded.Handle(context.Background(), "b/req")
+	require.NoError(t, err)
+	require.Equal(t, "global", resp)
+
+	sharded.RemoveAll(ids)
+	resp, err = sharded.Handle(context.Background(), "b/req")
+	require.NoError(t, err)
+	require.Equal(t, "", resp)
+}
+
+func TestShardedConcurrentMutation(t *testing.T) {
+	sharded := mutableware.NewSharded[int, int](4, func(request int) string {
+		return fmt.Sprint(request)
+	})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 16; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shard := sharded.For(fmt.Sprint(i))
+			for j := 0; j < 100; j++ {
+				shard.Remove(shard.AddAnonymousHandler(nil))
+			}
+			_, err := sharded.Handle(context.Background(), i)
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestShardedExpvar(t *testing.T) {
+	sharded := mutableware.NewSharded[string, string](2, func(request string) string {
+		return request
+	}, mutableware.ContainerOptionExpvar("mutableware_sharded_test"))
+
+	require.NotNil(t, expvar.Get("mutableware_sharded_test.0"))
+	require.NotNil(t, expvar.Get("mutableware_sharded_test.1"))
+	require.Len(t, sharded.Shards(), 2)
+}
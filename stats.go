@@ -0,0 +1,94 @@
+package mutableware
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HandlerStats describes how a handler has performed.
+// Latencies include the time the handler spent waiting on next, and errors
+// include those that came from further down the chain.
+type HandlerStats struct {
+	Info         HandlerInfo
+	Calls        uint64
+	Errors       uint64
+	TotalLatency time.Duration
+	LastLatency  time.Duration
+}
+
+// ChainStats describes the size of a container and how often it changes.
+type ChainStats struct {
+	// Handlers is the number of handlers in the container, including
+	// fallback, fast path, and registered handlers, and finalizers.
+	Handlers int
+	// Mutations is the number of times the container has been changed.
+	Mutations uint64
+	// LastMutation is when the container was last changed. It's the zero
+	// Time if the container has never been changed.
+	LastMutation time.Time
+}
+
+// ChainStats returns the size of the container and how often it has
+// changed. Unlike Stats, it's always available.
+func (hc *HandlerContainer[Request, Response]) ChainStats() ChainStats {
+	hc.mux.RLock()
+	defer hc.mux.RUnlock()
+	return ChainStats{
+		Handlers:     hc.size(),
+		Mutations:    hc.version,
+		LastMutation: hc.lastMutation,
+	}
+}
+
+// statsObserver records HandlerStats for every handler in a container.
+type statsObserver struct {
+	handlers sync.Map // HandlerID -> *handlerStats
+}
+
+type handlerStats struct {
+	mux   sync.Mutex
+	stats HandlerStats
+}
+
+func (s *statsObserver) OnHandleStart(ctx context.Context, info HandlerInfo) {}
+
+func (s *statsObserver) OnHandleEnd(ctx context.Context, info HandlerInfo, duration time.Duration, err error) {
+	entry, ok := s.handlers.Load(info.ID)
+	if !ok {
+		entry, _ = s.handlers.LoadOrStore(info.ID, &handlerStats{stats: HandlerStats{Info: info}})
+	}
+	hs := entry.(*handlerStats)
+	hs.mux.Lock()
+	defer hs.mux.Unlock()
+	hs.stats.Calls++
+	if err != nil {
+		hs.stats.Errors++
+	}
+	hs.stats.TotalLatency += duration
+	hs.stats.LastLatency = duration
+}
+
+func (s *statsObserver) forget(id HandlerID) {
+	s.handlers.Delete(id)
+}
+
+// Stats returns the stats of every handler in the container that has been
+// called. Stats are only recorded if the container was created with
+// ContainerOptionStats; otherwise, Stats returns an empty map.
+// A handler's stats are dropped when it leaves the chain, whether it's
+// removed, swapped out, or wrapped in a canary pair.
+func (hc *HandlerContainer[Request, Response]) Stats() map[HandlerID]HandlerStats {
+	out := map[HandlerID]HandlerStats{}
+	if hc.stats == nil {
+		return out
+	}
+	hc.stats.handlers.Range(func(key, value any) bool {
+		hs := value.(*handlerStats)
+		hs.mux.Lock()
+		out[key.(HandlerID)] = hs.stats
+		hs.mux.Unlock()
+		return true
+	})
+	return out
+}
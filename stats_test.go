@@ -0,0 +1,75 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionStats())
+	failID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			if request == "fail" {
+				return "", errors.New("failed")
+			}
+			return request, nil
+		}, mutableware.AddOptionName("inner"))
+	outerID := hc.AddAnonymousHandler(nil)
+
+	for _, request := range []string{"a", "fail", "b"} {
+		_, _ = hc.Handle(context.Background(), request)
+	}
+
+	stats := hc.Stats()
+	require.Len(t, stats, 2)
+	require.Equal(t, mutableware.HandlerInfo{ID: failID, Name: "inner"}, stats[failID].Info)
+	require.Equal(t, uint64(3), stats[failID].Calls)
+	require.Equal(t, uint64(1), stats[failID].Errors)
+	require.Equal(t, uint64(3), stats[outerID].Calls)
+	require.Equal(t, uint64(1), stats[outerID].Errors)
+	require.GreaterOrEqual(t, stats[outerID].TotalLatency, stats[failID].TotalLatency)
+	require.LessOrEqual(t, stats[failID].LastLatency, stats[failID].TotalLatency)
+
+	hc.Remove(outerID)
+	require.NotContains(t, hc.Stats(), outerID)
+
+	require.Empty(t, mutableware.NewHandlerContainer[string, string]().Stats())
+}
+
+func TestStatsSwap(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionStats())
+	oldID := hc.AddAnonymousHandler(nil)
+	_, _ = hc.Handle(context.Background(), "a")
+	require.Contains(t, hc.Stats(), oldID)
+
+	canaryID := hc.AddAnonymousHandler(nil, mutableware.AddOptionCanary(oldID, 50))
+	require.NotContains(t, hc.Stats(), oldID)
+	_, _ = hc.Handle(context.Background(), "a")
+	require.Contains(t, hc.Stats(), canaryID)
+
+	newID := hc.AddAnonymousHandler(nil, mutableware.AddOptionSwap(canaryID))
+	require.NotContains(t, hc.Stats(), canaryID)
+	_, _ = hc.Handle(context.Background(), "a")
+	require.Len(t, hc.Stats(), 1)
+	require.Equal(t, uint64(1), hc.Stats()[newID].Calls)
+}
+
+func TestChainStats(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	require.Equal(t, mutableware.ChainStats{}, hc.ChainStats())
+
+	before := time.Now()
+	id := hc.AddAnonymousHandler(nil)
+	hc.AddAnonymousHandler(nil, mutableware.AddOptionFallback())
+	hc.Remove(id)
+
+	stats := hc.ChainStats()
+	require.Equal(t, 1, stats.Handlers)
+	require.Equal(t, uint64(3), stats.Mutations)
+	require.False(t, stats.LastMutation.Before(before))
+}
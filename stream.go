@@ -0,0 +1,53 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoStream is returned by Emit when the context isn't attached to a
+// stream of the right Response type.
+var ErrNoStream = errors.New("noStream")
+
+// StreamFunc receives responses that handlers emit while a request is
+// being handled by HandleStream(...).
+type StreamFunc[Response any] func(ctx context.Context, response Response) error
+
+// HandleStream runs the Handle function of the contained handlers, allowing
+// them to send any number of intermediate responses to stream via Emit(...)
+// before the final Response is returned.
+func (hc *HandlerContainer[Request, Response]) HandleStream(ctx context.Context, request Request, stream StreamFunc[Response]) (Response, error) {
+	return hc.Handle(ContextWithStream(ctx, stream), request)
+}
+
+// ContextWithStream attaches a stream to the context. Handlers can use this
+// to intercept responses emitted by downstream handlers, by wrapping the
+// stream returned from GetStreamFromContext(...) and passing the new context
+// to next.
+func ContextWithStream[Response any](parent context.Context, stream StreamFunc[Response]) context.Context {
+	if stream == nil {
+		stream = nilStreamFunc[Response]
+	}
+	return context.WithValue(parent, streamCtxKey, stream)
+}
+
+// GetStreamFromContext returns the stream attached to the context.
+func GetStreamFromContext[Response any](ctx context.Context) (StreamFunc[Response], bool) {
+	stream, ok := (ctx.Value(streamCtxKey)).(StreamFunc[Response])
+	return stream, ok
+}
+
+// Emit sends an intermediate response to the stream attached to the context.
+// ErrNoStream is returned if there is no such stream, which happens when the
+// request was sent with Handle(...) instead of HandleStream(...).
+func Emit[Response any](ctx context.Context, response Response) error {
+	stream, ok := GetStreamFromContext[Response](ctx)
+	if !ok {
+		return ErrNoStream
+	}
+	return stream(ctx, response)
+}
+
+func nilStreamFunc[Response any](ctx context.Context, response Response) error {
+	return nil
+}
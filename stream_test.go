@@ -0,0 +1,46 @@
+package mutableware_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleStream(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			for _, word := range strings.Fields(request) {
+				if err := mutableware.Emit(ctx, word); err != nil {
+					return "", err
+				}
+			}
+			return "done", nil
+		})
+	// intercept emitted responses
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			stream, ok := mutableware.GetStreamFromContext[string](ctx)
+			if !ok {
+				return next(ctx, request)
+			}
+			return next(mutableware.ContextWithStream(ctx, func(ctx context.Context, response string) error {
+				return stream(ctx, strings.ToUpper(response))
+			}), request)
+		})
+
+	received := []string{}
+	resp, err := hc.HandleStream(context.Background(), "a b c", func(ctx context.Context, response string) error {
+		received = append(received, response)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "done", resp)
+	require.Equal(t, []string{"A", "B", "C"}, received)
+
+	_, err = hc.Handle(context.Background(), "a b c")
+	require.ErrorIs(t, err, mutableware.ErrNoStream)
+}
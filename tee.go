@@ -0,0 +1,118 @@
+package mutableware
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+type builtTeeOptions struct {
+	async   bool
+	onError func(ctx context.Context, err error)
+	copyFn  any
+}
+
+// TeeOption is an option for the NewTee(...) function.
+type TeeOption func(*builtTeeOptions)
+
+// TeeOptionAsync sends requests to the secondary container on a separate
+// goroutine, so the primary chain doesn't wait for it. The secondary
+// container gets a context that isn't canceled when the request's is.
+func TeeOptionAsync() TeeOption {
+	return func(o *builtTeeOptions) {
+		o.async = true
+	}
+}
+
+// TeeOptionOnError receives errors returned by the secondary container.
+// When TeeOptionAsync is used, panics are reported here too, as a
+// *PanicError identifying the tee.
+func TeeOptionOnError(onError func(ctx context.Context, err error)) TeeOption {
+	return func(o *builtTeeOptions) {
+		o.onError = onError
+	}
+}
+
+// TeeOptionCopy sends the secondary container the result of copyFn instead
+// of the request itself. Use this when Request is a pointer or contains
+// references that the secondary container shouldn't share.
+// The Request type must match the handler's, or NewTee panics.
+func TeeOptionCopy[Request any](copyFn func(Request) Request) TeeOption {
+	return func(o *builtTeeOptions) {
+		o.copyFn = copyFn
+	}
+}
+
+func buildTeeOptions(opts []TeeOption) *builtTeeOptions {
+	built := &builtTeeOptions{}
+	for _, opt := range opts {
+		opt(built)
+	}
+	return built
+}
+
+// teeHandler mirrors requests to a secondary container.
+type teeHandler[Request any, Response any] struct {
+	secondary *HandlerContainer[Request, Response]
+	async     bool
+	onError   func(ctx context.Context, err error)
+	copyFn    func(Request) Request
+}
+
+// NewTee creates a Handler that sends each request to secondary, and then
+// continues down the chain. The secondary container can't affect the
+// chain: its response is discarded, and its errors are only sent to
+// TeeOptionOnError.
+func NewTee[Request any, Response any](secondary *HandlerContainer[Request, Response], options ...TeeOption) Handler[Request, Response] {
+	teeOpts := buildTeeOptions(options)
+	copyFn, ok := teeOpts.copyFn.(func(Request) Request)
+	if teeOpts.copyFn != nil && !ok {
+		panic(fmt.Sprintf("mutableware: TeeOptionCopy needs a %T, not a %T", copyFn, teeOpts.copyFn))
+	}
+	return &teeHandler[Request, Response]{
+		secondary: secondary,
+		async:     teeOpts.async,
+		onError:   teeOpts.onError,
+		copyFn:    copyFn,
+	}
+}
+
+func (t *teeHandler[Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	mirrored := request
+	if t.copyFn != nil {
+		mirrored = t.copyFn(request)
+	}
+	if t.async {
+		teeCtx := context.WithoutCancel(ctx)
+		go func() {
+			t.report(teeCtx, t.run(teeCtx, mirrored))
+		}()
+	} else {
+		_, err := t.secondary.Handle(ctx, mirrored)
+		t.report(ctx, err)
+	}
+	return next(ctx, request)
+}
+
+// run sends a request to the secondary container on the tee's own
+// goroutine, where nothing else can recover a panic.
+func (t *teeHandler[Request, Response]) run(ctx context.Context, request Request) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+			panicErr.Info, _ = GetCurrentHandlerInfoFromContext(ctx)
+			err = panicErr
+		}
+	}()
+	_, err = t.secondary.Handle(ctx, request)
+	return err
+}
+
+func (t *teeHandler[Request, Response]) report(ctx context.Context, err error) {
+	if err != nil && t.onError != nil {
+		t.onError(ctx, err)
+	}
+}
@@ -0,0 +1,82 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTee(t *testing.T) {
+	mirrored := []string{}
+	secondary := mutableware.NewHandlerContainer[string, string]()
+	secondary.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			mirrored = append(mirrored, request)
+			return "ignored", errors.New("analytics down")
+		})
+
+	errs := []error{}
+	hc := respondWith("primary")
+	hc.Add(mutableware.NewTee(secondary, mutableware.TeeOptionOnError(func(ctx context.Context, err error) {
+		errs = append(errs, err)
+	})))
+
+	resp, err := hc.Handle(context.Background(), "req")
+	require.NoError(t, err)
+	require.Equal(t, "primary", resp)
+	require.Equal(t, []string{"req"}, mirrored)
+	require.Len(t, errs, 1)
+}
+
+func TestTeeAsync(t *testing.T) {
+	type request struct {
+		Tags []string
+	}
+
+	wg := sync.WaitGroup{}
+	secondary := mutableware.NewHandlerContainer[*request, string]()
+	secondary.AddAnonymousHandler(
+		func(ctx context.Context, request *request, next mutableware.CurriedHandlerFunc[*request, string]) (string, error) {
+			request.Tags = append(request.Tags, "secondary")
+			panic("oh no")
+		})
+
+	var teeErr error
+	hc := mutableware.NewHandlerContainer[*request, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request *request, next mutableware.CurriedHandlerFunc[*request, string]) (string, error) {
+			return "primary", nil
+		})
+	wg.Add(1)
+	teeID := hc.Add(mutableware.NewTee(secondary,
+		mutableware.TeeOptionAsync(),
+		mutableware.TeeOptionCopy(func(r *request) *request {
+			return &request{Tags: append([]string{}, r.Tags...)}
+		}),
+		mutableware.TeeOptionOnError(func(ctx context.Context, err error) {
+			teeErr = err
+			wg.Done()
+		})))
+
+	original := &request{}
+	resp, err := hc.Handle(context.Background(), original)
+	require.NoError(t, err)
+	require.Equal(t, "primary", resp)
+	wg.Wait()
+
+	require.Empty(t, original.Tags)
+	var panicErr *mutableware.PanicError
+	require.ErrorAs(t, teeErr, &panicErr)
+	require.Equal(t, teeID, panicErr.Info.ID)
+}
+
+func TestTeeCopyTypeMismatch(t *testing.T) {
+	secondary := mutableware.NewHandlerContainer[string, string]()
+	require.Panics(t, func() {
+		mutableware.NewTee(secondary, mutableware.TeeOptionCopy(func(r int) int { return r }))
+	})
+}
@@ -0,0 +1,79 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeoutError is returned when a handler takes longer than the time it was
+// given with AddOptionTimeout. It matches context.DeadlineExceeded.
+type TimeoutError struct {
+	// Info identifies the handler that timed out.
+	Info HandlerInfo
+	// Timeout is how long the handler was given.
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("handler %s timed out after %s", e.Info, e.Timeout)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Retryable reports that the handler can be tried again, since it was the
+// handler's own time limit that ran out rather than the request's.
+func (e *TimeoutError) Retryable() bool {
+	return true
+}
+
+// timeoutHandler bounds how long a handler can run.
+type timeoutHandler[Request any, Response any] struct {
+	Handler[Request, Response]
+	info    HandlerInfo
+	timeout time.Duration
+	clock   Clock
+}
+
+type timeoutResult[Response any] struct {
+	response Response
+	err      error
+	panicked any
+}
+
+func (t *timeoutHandler[Request, Response]) Handle(ctx context.Context, request Request, next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	timeoutErr := &TimeoutError{Info: t.info, Timeout: t.timeout}
+	ctx, cancel := withTimeoutCause(ctx, t.clock, t.timeout, timeoutErr)
+	defer cancel()
+
+	// the handler runs on its own goroutine so that it's bounded even if it
+	// ignores the context.
+	done := make(chan timeoutResult[Response], 1)
+	go func() {
+		var result timeoutResult[Response]
+		defer func() {
+			if r := recover(); r != nil {
+				result.panicked = r
+			}
+			done <- result
+		}()
+		result.response, result.err = t.Handler.Handle(ctx, request, next)
+	}()
+
+	select {
+	case result := <-done:
+		if result.panicked != nil {
+			panic(result.panicked)
+		}
+		if errors.Is(result.err, context.DeadlineExceeded) && context.Cause(ctx) == timeoutErr {
+			return result.response, timeoutErr
+		}
+		return result.response, result.err
+	case <-ctx.Done():
+		var zero Response
+		return zero, context.Cause(ctx)
+	}
+}
@@ -0,0 +1,57 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeout(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[time.Duration, string]()
+	release := make(chan struct{})
+	defer close(release)
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request time.Duration, next mutableware.CurriedHandlerFunc[time.Duration, string]) (string, error) {
+			// ignore the context, to make sure the handler is still bounded.
+			select {
+			case <-time.After(request):
+			case <-release:
+			}
+			return "done", nil
+		})
+	slowID := hc.AddAnonymousHandler(nil,
+		mutableware.AddOptionName("slow"),
+		mutableware.AddOptionTimeout(20*time.Millisecond))
+
+	resp, err := hc.Handle(context.Background(), 0)
+	require.NoError(t, err)
+	require.Equal(t, "done", resp)
+
+	_, err = hc.Handle(context.Background(), time.Hour)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	var timeoutErr *mutableware.TimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	require.Equal(t, mutableware.HandlerInfo{ID: slowID, Name: "slow"}, timeoutErr.Info)
+	require.Equal(t, 20*time.Millisecond, timeoutErr.Timeout)
+	require.True(t, mutableware.IsRetryable(context.Background(), err))
+}
+
+func TestTimeoutCallerCancelled(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}, mutableware.AddOptionTimeout(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := hc.Handle(ctx, "req")
+	require.ErrorIs(t, err, context.Canceled)
+	var timeoutErr *mutableware.TimeoutError
+	require.False(t, errors.As(err, &timeoutErr))
+}
@@ -0,0 +1,91 @@
+package mutableware
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ExecutionTraceEntry describes a single handler execution in a trace.
+type ExecutionTraceEntry struct {
+	Info  HandlerInfo
+	Start time.Time
+	// Duration includes the time the handler spent waiting on next.
+	// It's 0 if the handler is still running.
+	Duration time.Duration
+	// Err is the error the handler returned, which may have come from
+	// further down the chain.
+	Err error
+	// CalledNext is true if the handler called next.
+	CalledNext bool
+}
+
+// executionTrace collects the entries of a request's trace.
+type executionTrace struct {
+	mux     sync.Mutex
+	entries []ExecutionTraceEntry
+}
+
+// ContextWithExecutionTrace makes requests sent with the context record
+// every handler they run in, which can be read back with
+// GetExecutionTraceFromContext once Handle returns, or by the handlers
+// themselves. Recording a trace allocates, so only use it for requests that
+// need one.
+func ContextWithExecutionTrace(parent context.Context) context.Context {
+	return context.WithValue(parent, traceCtxKey, &executionTrace{})
+}
+
+// GetExecutionTraceFromContext returns the handlers that have run for the
+// request so far, in the order they were started. It returns nil if the
+// context wasn't made with ContextWithExecutionTrace.
+func GetExecutionTraceFromContext(ctx context.Context) []ExecutionTraceEntry {
+	trace := traceFrom(ctx)
+	if trace == nil {
+		return nil
+	}
+	trace.mux.Lock()
+	defer trace.mux.Unlock()
+	return append([]ExecutionTraceEntry{}, trace.entries...)
+}
+
+func traceFrom(ctx context.Context) *executionTrace {
+	if node, ok := ctx.(*handlerInfoCtx); ok {
+		return node.trace
+	}
+	trace, _ := (ctx.Value(traceCtxKey)).(*executionTrace)
+	return trace
+}
+
+// start records that a handler has started, and returns its entry's index.
+func (t *executionTrace) start(info HandlerInfo, now time.Time) int {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.entries = append(t.entries, ExecutionTraceEntry{
+		Info:  info,
+		Start: now,
+	})
+	return len(t.entries) - 1
+}
+
+func (t *executionTrace) end(idx int, now time.Time, calledNext bool, err error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	entry := &t.entries[idx]
+	entry.Duration = now.Sub(entry.Start)
+	entry.CalledNext = calledNext
+	entry.Err = err
+}
+
+// traced runs a single handler in the chain, recording it in the trace.
+func (hc *HandlerContainer[Request, Response]) traced(trace *executionTrace, handlerCtx context.Context, request Request, handler identifiedHandler[Request, Response], next CurriedHandlerFunc[Request, Response]) (Response, error) {
+	idx := trace.start(handler.info, hc.opts.clock.Now())
+	var calledNext atomic.Bool
+	tracedNext := func(ctx context.Context, request Request) (Response, error) {
+		calledNext.Store(true)
+		return next(ctx, request)
+	}
+	out, err := hc.profile(handlerCtx, request, handler, tracedNext)
+	trace.end(idx, hc.opts.clock.Now(), calledNext.Load(), err)
+	return out, err
+}
@@ -0,0 +1,47 @@
+package mutableware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionTrace(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string]()
+	failure := errors.New("failed")
+	innerID := hc.AddAnonymousHandler(
+		func(ctx context.Context, request string, next mutableware.CurriedHandlerFunc[string, string]) (string, error) {
+			require.Len(t, mutableware.GetExecutionTraceFromContext(ctx), 2)
+			return "", failure
+		}, mutableware.AddOptionName("inner"))
+	outerID := hc.AddAnonymousHandler(nil, mutableware.AddOptionName("outer"))
+
+	ctx := mutableware.ContextWithExecutionTrace(context.Background())
+	_, err := hc.Handle(ctx, "req")
+	require.ErrorIs(t, err, failure)
+
+	trace := mutableware.GetExecutionTraceFromContext(ctx)
+	require.Len(t, trace, 2)
+	require.Equal(t, mutableware.HandlerInfo{ID: outerID, Name: "outer"}, trace[0].Info)
+	require.True(t, trace[0].CalledNext)
+	require.ErrorIs(t, trace[0].Err, failure)
+	require.Equal(t, mutableware.HandlerInfo{ID: innerID, Name: "inner"}, trace[1].Info)
+	require.False(t, trace[1].CalledNext)
+	require.False(t, trace[1].Start.Before(trace[0].Start))
+	require.LessOrEqual(t, trace[1].Duration, trace[0].Duration)
+
+	require.Nil(t, mutableware.GetExecutionTraceFromContext(context.Background()))
+}
+
+func TestExecutionTraceWithoutHandlerInfo(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[string, string](mutableware.ContainerOptionWithoutHandlerInfo())
+	hc.AddAnonymousHandler(nil)
+
+	ctx := mutableware.ContextWithExecutionTrace(context.Background())
+	_, err := hc.Handle(ctx, "req")
+	require.NoError(t, err)
+	require.Len(t, mutableware.GetExecutionTraceFromContext(ctx), 1)
+}
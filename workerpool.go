@@ -0,0 +1,98 @@
+package mutableware
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrPoolClosed is returned when submitting a request to a WorkerPool
+// that has been closed.
+var ErrPoolClosed = errors.New("poolClosed")
+
+// WorkerPool owns a fixed number of goroutines that handle requests
+// submitted to it with a HandlerContainer.
+type WorkerPool[Request any, Response any] struct {
+	hc     *HandlerContainer[Request, Response]
+	queue  chan poolJob[Request, Response]
+	wg     sync.WaitGroup
+	mux    *sync.RWMutex
+	closed bool
+}
+
+type poolJob[Request any, Response any] struct {
+	ctx     context.Context
+	request Request
+	out     chan Result[Response]
+}
+
+// NewWorkerPool creates a WorkerPool that sends requests to hc.
+// Use BatchOptionConcurrency to set the number of workers and
+// BatchOptionQueueSize to set how many submitted requests can be waiting
+// for a worker.
+func NewWorkerPool[Request any, Response any](hc *HandlerContainer[Request, Response], options ...BatchOption) *WorkerPool[Request, Response] {
+	batchOpts := buildBatchOptions(options)
+	workers := max(batchOpts.concurrency, 1)
+
+	wp := &WorkerPool[Request, Response]{
+		hc:    hc,
+		queue: make(chan poolJob[Request, Response], max(batchOpts.queueSize, 0)),
+		mux:   &sync.RWMutex{},
+	}
+	wp.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go wp.work()
+	}
+	return wp
+}
+
+func (wp *WorkerPool[Request, Response]) work() {
+	defer wp.wg.Done()
+	for job := range wp.queue {
+		var result Result[Response]
+		if err := job.ctx.Err(); err != nil {
+			result.Err = err
+		} else {
+			result.Response, result.Err = wp.hc.Handle(job.ctx, job.request)
+		}
+		job.out <- result
+		close(job.out)
+	}
+}
+
+// Submit queues a request for a worker. It blocks until the request is
+// queued or ctx is done. The returned channel receives exactly one Result
+// and is then closed.
+func (wp *WorkerPool[Request, Response]) Submit(ctx context.Context, request Request) (<-chan Result[Response], error) {
+	wp.mux.RLock()
+	defer wp.mux.RUnlock()
+
+	if wp.closed {
+		return nil, ErrPoolClosed
+	}
+
+	job := poolJob[Request, Response]{
+		ctx:     ctx,
+		request: request,
+		out:     make(chan Result[Response], 1),
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case wp.queue <- job:
+		return job.out, nil
+	}
+}
+
+// Close stops accepting new requests and waits for all queued requests
+// to finish.
+func (wp *WorkerPool[Request, Response]) Close() {
+	wp.mux.Lock()
+	if !wp.closed {
+		wp.closed = true
+		close(wp.queue)
+	}
+	wp.mux.Unlock()
+
+	wp.wg.Wait()
+}
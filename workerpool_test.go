@@ -0,0 +1,54 @@
+package mutableware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erinpentecost/mutableware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPool(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, int]()
+	hc.AddAnonymousHandler(
+		func(ctx context.Context, request int, next mutableware.CurriedHandlerFunc[int, int]) (int, error) {
+			return request + 1, nil
+		})
+
+	wp := mutableware.NewWorkerPool(hc, mutableware.BatchOptionConcurrency(3), mutableware.BatchOptionQueueSize(10))
+
+	pending := []<-chan mutableware.Result[int]{}
+	for i := 0; i < 20; i++ {
+		out, err := wp.Submit(context.Background(), i)
+		require.NoError(t, err)
+		pending = append(pending, out)
+	}
+	wp.Close()
+
+	for i, out := range pending {
+		result := <-out
+		require.NoError(t, result.Err)
+		require.Equal(t, i+1, result.Response)
+	}
+
+	_, err := wp.Submit(context.Background(), 1)
+	require.ErrorIs(t, err, mutableware.ErrPoolClosed)
+
+	// closing twice is fine
+	wp.Close()
+}
+
+func TestWorkerPoolCanceled(t *testing.T) {
+	hc := mutableware.NewHandlerContainer[int, int]()
+	wp := mutableware.NewWorkerPool(hc)
+	defer wp.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out, err := wp.Submit(ctx, 1)
+	if err == nil {
+		require.ErrorIs(t, (<-out).Err, context.Canceled)
+	} else {
+		require.ErrorIs(t, err, context.Canceled)
+	}
+}